@@ -1,10 +1,18 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
 	"travel-blog-backend/internal/config"
 	"travel-blog-backend/internal/database"
+	"travel-blog-backend/internal/jobs"
 	"travel-blog-backend/internal/routes"
+	"travel-blog-backend/internal/views"
 )
 
 func main() {
@@ -12,12 +20,38 @@ func main() {
 	config.LoadConfig()
 	database.Connect()
 	database.Migrate()
+	database.SeedPlaces()
 	router := routes.SetupRoutes()
 
-	
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	jobs.Start(ctx)
+
+	server := &http.Server{
+		Addr:    ":" + config.AppConfig.Port,
+		Handler: router,
+	}
+
+	shutdownDone := make(chan struct{})
+	go func() {
+		defer close(shutdownDone)
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Println("Failed to shut down server:", err)
+		}
+	}()
+
 	log.Printf("Server starting on port %s", config.AppConfig.Port)
-	if err := router.Run(":" + config.AppConfig.Port); err != nil {
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		log.Fatal("Failed to start server:", err)
 	}
-}
 
+	// Wait for in-flight requests, then write out the views they recorded.
+	<-shutdownDone
+	if err := views.Flush(context.Background()); err != nil {
+		log.Println("Failed to flush post views:", err)
+	}
+}
@@ -3,6 +3,8 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -14,6 +16,32 @@ type Config struct {
 	JWTExpiry    int
 	RefreshExpiry int
 	Environment  string
+	PublishCheckInterval int
+	TrashRetentionDays   int
+	StorageDriver        string
+	UploadsDir           string
+	MediaBaseURL         string
+	MaxUploadMB          int
+	S3Endpoint           string
+	S3Region             string
+	S3Bucket             string
+	S3AccessKey          string
+	S3SecretKey          string
+	S3PublicURL          string
+	ViewFlushInterval    int
+	TrendingHalfLife     int
+	PreviewLinkExpiry    int
+	CommentMaxDepth      int
+	CommentRepliesPerBranch int
+	CommentThreadDepth      int
+	CommentModeration       string
+	SpamHoldScore           int
+	SpamRejectScore         int
+	SpamMaxLinks            int
+	SpamBlockedDomains      []string
+	SpamMinTraining         int
+	ReportHideThreshold     int
+	TrustedProxies          []string
 }
 
 var AppConfig *Config
@@ -31,5 +59,56 @@ func LoadConfig() {
 		JWTExpiry:    24,
 		RefreshExpiry: 168,
 		Environment:  os.Getenv("ENVIRONMENT"),
+		PublishCheckInterval: getEnvInt("PUBLISH_CHECK_INTERVAL", 30),
+		TrashRetentionDays:   getEnvInt("TRASH_RETENTION_DAYS", 30),
+		StorageDriver:        getEnv("STORAGE_DRIVER", "local"),
+		UploadsDir:           getEnv("UPLOADS_DIR", "./uploads"),
+		MediaBaseURL:         getEnv("MEDIA_BASE_URL", "/api/uploads"),
+		MaxUploadMB:          getEnvInt("MAX_UPLOAD_MB", 10),
+		S3Endpoint:           os.Getenv("S3_ENDPOINT"),
+		S3Region:             os.Getenv("S3_REGION"),
+		S3Bucket:             os.Getenv("S3_BUCKET"),
+		S3AccessKey:          os.Getenv("S3_ACCESS_KEY"),
+		S3SecretKey:          os.Getenv("S3_SECRET_KEY"),
+		S3PublicURL:          os.Getenv("S3_PUBLIC_URL"),
+		ViewFlushInterval:    getEnvInt("VIEW_FLUSH_INTERVAL", 10),
+		TrendingHalfLife:     getEnvInt("TRENDING_HALF_LIFE_HOURS", 48),
+		PreviewLinkExpiry:    getEnvInt("PREVIEW_LINK_EXPIRY_HOURS", 72),
+		CommentMaxDepth:      getEnvInt("COMMENT_MAX_DEPTH", 8),
+		CommentRepliesPerBranch: getEnvInt("COMMENT_REPLIES_PER_BRANCH", 5),
+		CommentThreadDepth:      getEnvInt("COMMENT_THREAD_DEPTH", 3),
+		CommentModeration:       getEnv("COMMENT_MODERATION", "open"),
+		SpamHoldScore:           getEnvInt("SPAM_HOLD_SCORE", 5),
+		SpamRejectScore:         getEnvInt("SPAM_REJECT_SCORE", 10),
+		SpamMaxLinks:            getEnvInt("SPAM_MAX_LINKS", 2),
+		SpamBlockedDomains:      getEnvList("SPAM_BLOCKED_DOMAINS"),
+		SpamMinTraining:         getEnvInt("SPAM_MIN_TRAINING", 20),
+		ReportHideThreshold:     getEnvInt("REPORT_HIDE_THRESHOLD", 3),
+		TrustedProxies:          getEnvList("TRUSTED_PROXIES"),
 	}
 }
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func getEnvInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
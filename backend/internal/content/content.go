@@ -0,0 +1,299 @@
+package content
+
+import (
+	"bytes"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"travel-blog-backend/internal/models"
+	"unicode"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer/html"
+	xhtml "golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+const wordsPerMinute = 200
+
+type Rendered struct {
+	HTML        string
+	TOC         models.TableOfContents
+	ReadingTime int
+	Text        string
+}
+
+var markdown = goldmark.New(
+	goldmark.WithExtensions(extension.GFM, extension.Footnote),
+	// Raw HTML is passed through here and cleaned by the sanitizer below, so
+	// markdown posts can still embed the same tags HTML posts may use.
+	goldmark.WithRendererOptions(html.WithUnsafe()),
+)
+
+var policy = newPolicy()
+
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("id").Matching(regexp.MustCompile(`^[\p{L}\p{N}_-]+$`)).
+		OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+-]+$`)).OnElements("code")
+	p.AllowAttrs("loading").Matching(regexp.MustCompile(`^lazy$`)).OnElements("img")
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+	return p
+}
+
+func ParseFormat(value string) (models.ContentFormat, bool) {
+	switch models.ContentFormat(value) {
+	case "", models.FormatMarkdown:
+		return models.FormatMarkdown, true
+	case models.FormatHTML:
+		return models.FormatHTML, true
+	}
+	return "", false
+}
+
+// Render converts source to sanitized HTML, assigns anchor IDs to headings
+// and derives the table of contents, plain text and reading time.
+func Render(format models.ContentFormat, source string) (Rendered, error) {
+	raw := source
+	if format == models.FormatMarkdown {
+		var buf bytes.Buffer
+		if err := markdown.Convert([]byte(source), &buf); err != nil {
+			return Rendered{}, err
+		}
+		raw = buf.String()
+	}
+
+	safe := policy.Sanitize(raw)
+
+	nodes, err := xhtml.ParseFragment(strings.NewReader(safe), &xhtml.Node{
+		Type:     xhtml.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+	if err != nil {
+		return Rendered{}, err
+	}
+
+	var text strings.Builder
+	toc := make(models.TableOfContents, 0)
+	ids := make(map[string]int)
+	for _, node := range nodes {
+		walk(node, &text, &toc, ids)
+	}
+
+	var out strings.Builder
+	for _, node := range nodes {
+		if err := xhtml.Render(&out, node); err != nil {
+			return Rendered{}, err
+		}
+	}
+
+	plain := strings.Join(strings.Fields(text.String()), " ")
+	return Rendered{
+		HTML:        out.String(),
+		TOC:         toc,
+		ReadingTime: ReadingTime(plain),
+		Text:        plain,
+	}, nil
+}
+
+func walk(node *xhtml.Node, text *strings.Builder, toc *models.TableOfContents, ids map[string]int) {
+	switch node.Type {
+	case xhtml.TextNode:
+		text.WriteString(node.Data)
+		return
+	case xhtml.ElementNode:
+		if level := headingLevel(node.DataAtom); level > 0 {
+			title := strings.Join(strings.Fields(nodeText(node)), " ")
+			id := getAttr(node, "id")
+			if id == "" {
+				id = slug(title)
+			}
+			if id != "" {
+				if n := ids[id]; n > 0 {
+					ids[id] = n + 1
+					id = id + "-" + strconv.Itoa(n)
+				} else {
+					ids[id] = 1
+				}
+				setAttr(node, "id", id)
+				*toc = append(*toc, models.TOCEntry{Level: level, Text: title, ID: id})
+			}
+		}
+	}
+
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		walk(child, text, toc, ids)
+	}
+
+	if node.Type == xhtml.ElementNode && isBlock(node.DataAtom) {
+		text.WriteByte(' ')
+	}
+}
+
+func headingLevel(a atom.Atom) int {
+	switch a {
+	case atom.H1:
+		return 1
+	case atom.H2:
+		return 2
+	case atom.H3:
+		return 3
+	case atom.H4:
+		return 4
+	case atom.H5:
+		return 5
+	case atom.H6:
+		return 6
+	}
+	return 0
+}
+
+func isBlock(a atom.Atom) bool {
+	switch a {
+	case atom.P, atom.Div, atom.Br, atom.Li, atom.Tr, atom.Td, atom.Th, atom.Blockquote, atom.Pre,
+		atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		return true
+	}
+	return false
+}
+
+func nodeText(node *xhtml.Node) string {
+	if node.Type == xhtml.TextNode {
+		return node.Data
+	}
+	var b strings.Builder
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		b.WriteString(nodeText(child))
+	}
+	return b.String()
+}
+
+func getAttr(node *xhtml.Node, key string) string {
+	for _, attr := range node.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+func setAttr(node *xhtml.Node, key, value string) {
+	for i, attr := range node.Attr {
+		if attr.Key == key {
+			node.Attr[i].Val = value
+			return
+		}
+	}
+	node.Attr = append(node.Attr, xhtml.Attribute{Key: key, Val: value})
+}
+
+func slug(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+			dash = false
+		case !dash && b.Len() > 0:
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+// ReadingTime estimates the minutes needed to read text, rounding up and
+// never returning less than one minute for non-empty text.
+func ReadingTime(text string) int {
+	words := len(strings.Fields(text))
+	if words == 0 {
+		return 0
+	}
+	return int(math.Ceil(float64(words) / wordsPerMinute))
+}
+
+// Excerpt shortens text to at most maxRunes runes, cutting at a word boundary
+// and adding an ellipsis when anything was removed.
+func Excerpt(text string, maxRunes int) string {
+	runes := []rune(text)
+	if len(runes) <= maxRunes {
+		return text
+	}
+
+	cut := maxRunes
+	for cut > maxRunes/2 && !unicode.IsSpace(runes[cut]) {
+		cut--
+	}
+	return strings.TrimRightFunc(string(runes[:cut]), func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	}) + "…"
+}
+
+const excerptLength = 200
+
+// fields points at the content columns shared by posts and translations.
+type fields struct {
+	format           *models.ContentFormat
+	content          string
+	html             *string
+	toc              *models.TableOfContents
+	readingTime      *int
+	excerpt          **string
+	excerptGenerated *bool
+}
+
+// apply renders the content and stores the derived fields. A missing or
+// previously generated excerpt is (re)generated from the text.
+func apply(f fields) error {
+	if *f.format == "" {
+		*f.format = models.FormatMarkdown
+	}
+
+	rendered, err := Render(*f.format, f.content)
+	if err != nil {
+		return err
+	}
+
+	*f.html = rendered.HTML
+	*f.toc = rendered.TOC
+	*f.readingTime = rendered.ReadingTime
+	if *f.excerptGenerated || *f.excerpt == nil || strings.TrimSpace(**f.excerpt) == "" {
+		excerpt := Excerpt(rendered.Text, excerptLength)
+		*f.excerpt = &excerpt
+		*f.excerptGenerated = true
+	}
+	return nil
+}
+
+// ApplyToPost renders the post's content and stores the derived fields on it.
+// Posts without a hand-written excerpt get one generated from the text.
+func ApplyToPost(post *models.Post) error {
+	return apply(fields{
+		format:           &post.ContentFormat,
+		content:          post.Content,
+		html:             &post.ContentHTML,
+		toc:              &post.TOC,
+		readingTime:      &post.ReadingTime,
+		excerpt:          &post.Excerpt,
+		excerptGenerated: &post.ExcerptGenerated,
+	})
+}
+
+// ApplyToTranslation renders a translation like ApplyToPost renders a post.
+func ApplyToTranslation(translation *models.PostTranslation) error {
+	return apply(fields{
+		format:           &translation.ContentFormat,
+		content:          translation.Content,
+		html:             &translation.ContentHTML,
+		toc:              &translation.TOC,
+		readingTime:      &translation.ReadingTime,
+		excerpt:          &translation.Excerpt,
+		excerptGenerated: &translation.ExcerptGenerated,
+	})
+}
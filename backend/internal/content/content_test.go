@@ -0,0 +1,266 @@
+package content
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"travel-blog-backend/internal/models"
+)
+
+func TestRenderSanitizes(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  models.ContentFormat
+		source  string
+		absent  []string
+		present []string
+	}{
+		{
+			name:    "script tag in html",
+			format:  models.FormatHTML,
+			source:  `<p>Hello</p><script>alert(1)</script>`,
+			absent:  []string{"<script", "alert(1)"},
+			present: []string{"<p>Hello</p>"},
+		},
+		{
+			name:   "script tag in markdown",
+			format: models.FormatMarkdown,
+			source: "Hello\n\n<script>alert(1)</script>\n",
+			absent: []string{"<script", "alert(1)"},
+		},
+		{
+			name:    "javascript link in html",
+			format:  models.FormatHTML,
+			source:  `<a href="javascript:alert(1)">click</a>`,
+			absent:  []string{"javascript:"},
+			present: []string{"click"},
+		},
+		{
+			name:    "javascript link in markdown",
+			format:  models.FormatMarkdown,
+			source:  "[click](javascript:alert(1))",
+			absent:  []string{"javascript:"},
+			present: []string{"click"},
+		},
+		{
+			name:    "event handler attributes",
+			format:  models.FormatHTML,
+			source:  `<img src="https://example.com/a.jpg" onerror="alert(1)"><p onclick="alert(2)">Hi</p>`,
+			absent:  []string{"onerror", "onclick", "alert("},
+			present: []string{`src="https://example.com/a.jpg"`, "Hi"},
+		},
+		{
+			name:    "raw html inside markdown",
+			format:  models.FormatMarkdown,
+			source:  "Intro\n\n<div style=\"position:fixed\"><iframe src=\"https://evil.example\"></iframe><b>bold</b></div>\n",
+			absent:  []string{"<iframe", "style=", "evil.example"},
+			present: []string{"<b>bold</b>"},
+		},
+		{
+			name:    "external links open in a new tab",
+			format:  models.FormatMarkdown,
+			source:  "[site](https://example.com)",
+			present: []string{`href="https://example.com"`, `target="_blank"`, `rel="nofollow noopener"`},
+		},
+		{
+			name:    "code block language class",
+			format:  models.FormatMarkdown,
+			source:  "```go\nfmt.Println()\n```\n",
+			present: []string{`class="language-go"`},
+		},
+		{
+			name:   "quotes in attribute values stay escaped",
+			format: models.FormatHTML,
+			source: `<h2 id="x&quot; onmouseover=&quot;alert(1)">Title</h2>`,
+			absent: []string{`onmouseover="`},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rendered, err := Render(tc.format, tc.source)
+			if err != nil {
+				t.Fatalf("Render: %v", err)
+			}
+			for _, s := range tc.absent {
+				if strings.Contains(rendered.HTML, s) {
+					t.Errorf("HTML contains %q: %s", s, rendered.HTML)
+				}
+			}
+			for _, s := range tc.present {
+				if !strings.Contains(rendered.HTML, s) {
+					t.Errorf("HTML lacks %q: %s", s, rendered.HTML)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderTableOfContents(t *testing.T) {
+	tests := []struct {
+		name   string
+		format models.ContentFormat
+		source string
+		want   models.TableOfContents
+	}{
+		{
+			name:   "markdown headings",
+			format: models.FormatMarkdown,
+			source: "# Day One\n\ntext\n\n## Getting *there*\n\n### Café & Bar\n",
+			want: models.TableOfContents{
+				{Level: 1, Text: "Day One", ID: "day-one"},
+				{Level: 2, Text: "Getting there", ID: "getting-there"},
+				{Level: 3, Text: "Café & Bar", ID: "café-bar"},
+			},
+		},
+		{
+			name:   "duplicate titles",
+			format: models.FormatHTML,
+			source: "<h2>Food</h2><h2>Food</h2><h2>Food</h2>",
+			want: models.TableOfContents{
+				{Level: 2, Text: "Food", ID: "food"},
+				{Level: 2, Text: "Food", ID: "food-1"},
+				{Level: 2, Text: "Food", ID: "food-2"},
+			},
+		},
+		{
+			name:   "existing ids are kept",
+			format: models.FormatHTML,
+			source: `<h2 id="intro">Introduction</h2>`,
+			want:   models.TableOfContents{{Level: 2, Text: "Introduction", ID: "intro"}},
+		},
+		{
+			name:   "headings without text are skipped",
+			format: models.FormatHTML,
+			source: "<h2>!!!</h2><p>text</p>",
+			want:   models.TableOfContents{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rendered, err := Render(tc.format, tc.source)
+			if err != nil {
+				t.Fatalf("Render: %v", err)
+			}
+			if !reflect.DeepEqual(rendered.TOC, tc.want) {
+				t.Errorf("TOC = %+v, want %+v", rendered.TOC, tc.want)
+			}
+			for _, entry := range tc.want {
+				if !strings.Contains(rendered.HTML, `id="`+entry.ID+`"`) {
+					t.Errorf("HTML lacks id %q: %s", entry.ID, rendered.HTML)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderTextAndReadingTime(t *testing.T) {
+	rendered, err := Render(models.FormatMarkdown, "# Title\n\nFirst *paragraph*.\n\n- one\n- two\n")
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if want := "Title First paragraph. one two"; rendered.Text != want {
+		t.Errorf("Text = %q, want %q", rendered.Text, want)
+	}
+	if rendered.ReadingTime != 1 {
+		t.Errorf("ReadingTime = %d, want 1", rendered.ReadingTime)
+	}
+}
+
+func TestReadingTime(t *testing.T) {
+	tests := []struct {
+		words int
+		want  int
+	}{
+		{0, 0},
+		{1, 1},
+		{wordsPerMinute, 1},
+		{wordsPerMinute + 1, 2},
+		{wordsPerMinute * 3, 3},
+	}
+	for _, tc := range tests {
+		if got := ReadingTime(strings.Repeat("word ", tc.words)); got != tc.want {
+			t.Errorf("ReadingTime(%d words) = %d, want %d", tc.words, got, tc.want)
+		}
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		max  int
+		want string
+	}{
+		{"short text is unchanged", "A short walk.", 20, "A short walk."},
+		{"exact length is unchanged", "abcde", 5, "abcde"},
+		{"cut at word boundary", "The quick brown fox jumps", 12, "The quick…"},
+		{"trailing punctuation dropped", "Hello, world again", 8, "Hello…"},
+		{"long word is cut at half the limit", "Supercalifragilistic", 10, "Super…"},
+		{"counts runes not bytes", "Ünïcödé wörds hère", 9, "Ünïcödé…"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Excerpt(tc.text, tc.max); got != tc.want {
+				t.Errorf("Excerpt(%q, %d) = %q, want %q", tc.text, tc.max, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestApplyToPostExcerpt(t *testing.T) {
+	handWritten := "My own summary"
+	blank := "   "
+	tests := []struct {
+		name          string
+		excerpt       *string
+		generated     bool
+		want          string
+		wantGenerated bool
+	}{
+		{"missing excerpt is generated", nil, false, "Body text", true},
+		{"blank excerpt is generated", &blank, false, "Body text", true},
+		{"hand-written excerpt is kept", &handWritten, false, handWritten, false},
+		{"generated excerpt is refreshed", &handWritten, true, "Body text", true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			post := models.Post{Content: "Body text", Excerpt: tc.excerpt, ExcerptGenerated: tc.generated}
+			if err := ApplyToPost(&post); err != nil {
+				t.Fatalf("ApplyToPost: %v", err)
+			}
+			if post.ContentFormat != models.FormatMarkdown {
+				t.Errorf("ContentFormat = %q, want markdown", post.ContentFormat)
+			}
+			if post.Excerpt == nil || *post.Excerpt != tc.want {
+				t.Errorf("Excerpt = %v, want %q", post.Excerpt, tc.want)
+			}
+			if post.ExcerptGenerated != tc.wantGenerated {
+				t.Errorf("ExcerptGenerated = %v, want %v", post.ExcerptGenerated, tc.wantGenerated)
+			}
+		})
+	}
+}
+
+func TestApplyToTranslation(t *testing.T) {
+	translation := models.PostTranslation{Content: "# Tag eins\n\nHallo Welt"}
+	if err := ApplyToTranslation(&translation); err != nil {
+		t.Fatalf("ApplyToTranslation: %v", err)
+	}
+	if translation.ContentFormat != models.FormatMarkdown {
+		t.Errorf("ContentFormat = %q, want markdown", translation.ContentFormat)
+	}
+	if !strings.Contains(translation.ContentHTML, `<h1 id="tag-eins">`) {
+		t.Errorf("ContentHTML = %s", translation.ContentHTML)
+	}
+	if want := (models.TableOfContents{{Level: 1, Text: "Tag eins", ID: "tag-eins"}}); !reflect.DeepEqual(translation.TOC, want) {
+		t.Errorf("TOC = %+v, want %+v", translation.TOC, want)
+	}
+	if translation.ReadingTime != 1 {
+		t.Errorf("ReadingTime = %d, want 1", translation.ReadingTime)
+	}
+	if translation.Excerpt == nil || *translation.Excerpt != "Tag eins Hallo Welt" || !translation.ExcerptGenerated {
+		t.Errorf("Excerpt = %v (generated %v), want %q", translation.Excerpt, translation.ExcerptGenerated, "Tag eins Hallo Welt")
+	}
+}
@@ -0,0 +1,119 @@
+package database
+
+import (
+	_ "embed"
+	"encoding/json"
+	"log"
+	"strings"
+	"travel-blog-backend/internal/models"
+	"unicode"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//go:embed seed/places.json
+var placesSeed []byte
+
+type seedPlace struct {
+	Name        string      `json:"name"`
+	Type        string      `json:"type"`
+	CountryCode *string     `json:"countryCode"`
+	Latitude    *float64    `json:"latitude"`
+	Longitude   *float64    `json:"longitude"`
+	Children    []seedPlace `json:"children"`
+}
+
+// SeedPlaces inserts any places from the bundled dataset that are not yet in
+// the database. Existing rows are matched by slug and left untouched, so the
+// seed is safe to run on every start, including from several instances at once.
+func SeedPlaces() {
+	var roots []seedPlace
+	if err := json.Unmarshal(placesSeed, &roots); err != nil {
+		log.Fatal("Failed to parse places dataset:", err)
+	}
+
+	var existing []models.Place
+	if err := DB.Select("id", "slug").Find(&existing).Error; err != nil {
+		log.Fatal("Failed to load places:", err)
+	}
+	ids := make(map[string]uint, len(existing))
+	for _, place := range existing {
+		ids[place.Slug] = place.ID
+	}
+
+	inserted := 0
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		var walk func(nodes []seedPlace, parentSlug string, parentID *uint, countryCode *string) error
+		walk = func(nodes []seedPlace, parentSlug string, parentID *uint, countryCode *string) error {
+			for _, node := range nodes {
+				slug := slugify(node.Name)
+				if parentSlug != "" {
+					slug = parentSlug + "/" + slug
+				}
+
+				code := countryCode
+				if node.CountryCode != nil {
+					code = node.CountryCode
+				}
+
+				id, ok := ids[slug]
+				if !ok {
+					place := models.Place{
+						Slug:        slug,
+						Name:        node.Name,
+						Type:        models.PlaceType(node.Type),
+						ParentID:    parentID,
+						CountryCode: code,
+						Latitude:    node.Latitude,
+						Longitude:   node.Longitude,
+					}
+					// Another instance may be seeding concurrently; let it win
+					// the insert and pick up the row it created.
+					result := tx.Clauses(clause.OnConflict{
+						Columns:   []clause.Column{{Name: "slug"}},
+						DoNothing: true,
+					}).Create(&place)
+					if result.Error != nil {
+						return result.Error
+					}
+					if result.RowsAffected == 0 {
+						if err := tx.Select("id").Where("slug = ?", slug).Take(&place).Error; err != nil {
+							return err
+						}
+					} else {
+						inserted++
+					}
+					id = place.ID
+					ids[slug] = id
+				}
+
+				if err := walk(node.Children, slug, &id, code); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		return walk(roots, "", nil, nil)
+	})
+	if err != nil {
+		log.Fatal("Failed to seed places:", err)
+	}
+
+	log.Printf("Places seeded (%d new)", inserted)
+}
+
+func slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteRune('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
@@ -0,0 +1,144 @@
+package gpx
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"math"
+	"strings"
+	"time"
+)
+
+// MaxPoints bounds the number of points accepted from a single file.
+const MaxPoints = 200_000
+
+var (
+	ErrInvalidFile   = errors.New("invalid GPX file")
+	ErrNoPoints      = errors.New("GPX file contains no track points")
+	ErrTooManyPoints = errors.New("GPX file contains too many points")
+)
+
+type Point struct {
+	Latitude  float64
+	Longitude float64
+	Elevation *float64
+	Time      *time.Time
+}
+
+// Track is a parsed GPX file. Every track segment and route becomes its own
+// segment; distances are never measured across segment boundaries.
+type Track struct {
+	Name     string
+	Segments [][]Point
+}
+
+type document struct {
+	Metadata struct {
+		Name string `xml:"name"`
+	} `xml:"metadata"`
+	Name   string `xml:"name"`
+	Tracks []struct {
+		Name     string `xml:"name"`
+		Segments []struct {
+			Points []point `xml:"trkpt"`
+		} `xml:"trkseg"`
+	} `xml:"trk"`
+	Routes []struct {
+		Name   string  `xml:"name"`
+		Points []point `xml:"rtept"`
+	} `xml:"rte"`
+}
+
+type point struct {
+	Lat  float64  `xml:"lat,attr"`
+	Lon  float64  `xml:"lon,attr"`
+	Ele  *float64 `xml:"ele"`
+	Time string   `xml:"time"`
+}
+
+// Parse reads a GPX 1.0 or 1.1 document.
+func Parse(r io.Reader) (*Track, error) {
+	var doc document
+	decoder := xml.NewDecoder(r)
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, ErrInvalidFile
+	}
+
+	track := &Track{Name: strings.TrimSpace(doc.Metadata.Name)}
+	if track.Name == "" {
+		track.Name = strings.TrimSpace(doc.Name)
+	}
+
+	total := 0
+	add := func(name string, raw []point) error {
+		if track.Name == "" {
+			track.Name = strings.TrimSpace(name)
+		}
+		if len(raw) == 0 {
+			return nil
+		}
+		total += len(raw)
+		if total > MaxPoints {
+			return ErrTooManyPoints
+		}
+
+		segment := make([]Point, 0, len(raw))
+		for _, p := range raw {
+			if p.Lat < -90 || p.Lat > 90 || p.Lon < -180 || p.Lon > 180 {
+				return ErrInvalidFile
+			}
+			pt := Point{Latitude: p.Lat, Longitude: p.Lon, Elevation: p.Ele}
+			if t, err := time.Parse(time.RFC3339, strings.TrimSpace(p.Time)); err == nil {
+				t = t.UTC()
+				pt.Time = &t
+			}
+			segment = append(segment, pt)
+		}
+		track.Segments = append(track.Segments, segment)
+		return nil
+	}
+
+	for _, trk := range doc.Tracks {
+		for _, seg := range trk.Segments {
+			if err := add(trk.Name, seg.Points); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, rte := range doc.Routes {
+		if err := add(rte.Name, rte.Points); err != nil {
+			return nil, err
+		}
+	}
+
+	if total == 0 {
+		return nil, ErrNoPoints
+	}
+	return track, nil
+}
+
+// Points returns all points of the track in order.
+func (t *Track) Points() []Point {
+	var points []Point
+	for _, segment := range t.Segments {
+		points = append(points, segment...)
+	}
+	return points
+}
+
+const earthRadiusMeters = 6371000.0
+
+// Distance returns the great-circle distance between a and b in meters.
+func Distance(a, b Point) float64 {
+	lat1, lat2 := radians(a.Latitude), radians(b.Latitude)
+	dLat := lat2 - lat1
+	dLon := radians(b.Longitude - a.Longitude)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
@@ -0,0 +1,149 @@
+package gpx
+
+import (
+	"math"
+	"time"
+)
+
+// elevationThreshold is the climb in meters that must be exceeded before it
+// counts towards the elevation gain, so GPS altitude noise on flat ground
+// does not add up to hundreds of phantom meters.
+const elevationThreshold = 3.0
+
+type Bounds struct {
+	MinLatitude  float64
+	MinLongitude float64
+	MaxLatitude  float64
+	MaxLongitude float64
+}
+
+type Stats struct {
+	Distance      float64
+	ElevationGain float64
+	Duration      time.Duration
+	StartedAt     *time.Time
+	EndedAt       *time.Time
+	Bounds        Bounds
+	PointCount    int
+}
+
+// Stats computes the summary figures of the track. Distance is in meters;
+// Duration is the span between the first and last timestamped points.
+func (t *Track) Stats() Stats {
+	stats := Stats{Bounds: Bounds{
+		MinLatitude:  math.Inf(1),
+		MinLongitude: math.Inf(1),
+		MaxLatitude:  math.Inf(-1),
+		MaxLongitude: math.Inf(-1),
+	}}
+
+	for _, segment := range t.Segments {
+		var reference *float64
+		for i, p := range segment {
+			stats.PointCount++
+			stats.Bounds.MinLatitude = math.Min(stats.Bounds.MinLatitude, p.Latitude)
+			stats.Bounds.MinLongitude = math.Min(stats.Bounds.MinLongitude, p.Longitude)
+			stats.Bounds.MaxLatitude = math.Max(stats.Bounds.MaxLatitude, p.Latitude)
+			stats.Bounds.MaxLongitude = math.Max(stats.Bounds.MaxLongitude, p.Longitude)
+
+			if i > 0 {
+				stats.Distance += Distance(segment[i-1], p)
+			}
+
+			if p.Elevation != nil {
+				switch {
+				case reference == nil:
+					reference = p.Elevation
+				case *p.Elevation-*reference >= elevationThreshold:
+					stats.ElevationGain += *p.Elevation - *reference
+					reference = p.Elevation
+				case *reference-*p.Elevation >= elevationThreshold:
+					reference = p.Elevation
+				}
+			}
+
+			if p.Time != nil {
+				if stats.StartedAt == nil || p.Time.Before(*stats.StartedAt) {
+					stats.StartedAt = p.Time
+				}
+				if stats.EndedAt == nil || p.Time.After(*stats.EndedAt) {
+					stats.EndedAt = p.Time
+				}
+			}
+		}
+	}
+
+	if stats.StartedAt != nil {
+		stats.Duration = stats.EndedAt.Sub(*stats.StartedAt)
+	}
+	return stats
+}
+
+// Simplify reduces points with the Douglas-Peucker algorithm, dropping every
+// point that lies within tolerance meters of the simplified line. The first
+// and last points are always kept.
+func Simplify(points []Point, tolerance float64) []Point {
+	if len(points) < 3 {
+		return append([]Point(nil), points...)
+	}
+
+	// Project onto a local plane in meters. An equirectangular projection
+	// around the mean latitude is accurate enough at track scale.
+	var meanLat float64
+	for _, p := range points {
+		meanLat += p.Latitude
+	}
+	meanLat /= float64(len(points))
+	scaleX := radians(1) * earthRadiusMeters * math.Cos(radians(meanLat))
+	scaleY := radians(1) * earthRadiusMeters
+
+	xs := make([]float64, len(points))
+	ys := make([]float64, len(points))
+	for i, p := range points {
+		xs[i] = p.Longitude * scaleX
+		ys[i] = p.Latitude * scaleY
+	}
+
+	keep := make([]bool, len(points))
+	keep[0], keep[len(points)-1] = true, true
+
+	type span struct{ first, last int }
+	stack := []span{{0, len(points) - 1}}
+	for len(stack) > 0 {
+		s := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		farthest, maxDist := -1, tolerance
+		for i := s.first + 1; i < s.last; i++ {
+			d := segmentDistance(xs[i], ys[i], xs[s.first], ys[s.first], xs[s.last], ys[s.last])
+			if d > maxDist {
+				farthest, maxDist = i, d
+			}
+		}
+		if farthest < 0 {
+			continue
+		}
+		keep[farthest] = true
+		stack = append(stack, span{s.first, farthest}, span{farthest, s.last})
+	}
+
+	simplified := make([]Point, 0)
+	for i, p := range points {
+		if keep[i] {
+			simplified = append(simplified, p)
+		}
+	}
+	return simplified
+}
+
+// segmentDistance returns the distance from (px, py) to the segment between
+// (ax, ay) and (bx, by).
+func segmentDistance(px, py, ax, ay, bx, by float64) float64 {
+	dx, dy := bx-ax, by-ay
+	if dx == 0 && dy == 0 {
+		return math.Hypot(px-ax, py-ay)
+	}
+	t := ((px-ax)*dx + (py-ay)*dy) / (dx*dx + dy*dy)
+	t = math.Max(0, math.Min(1, t))
+	return math.Hypot(px-(ax+t*dx), py-(ay+t*dy))
+}
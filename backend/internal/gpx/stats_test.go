@@ -0,0 +1,153 @@
+package gpx
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// metersPerDegree is the length of one degree of latitude on the sphere
+// Distance and Simplify assume.
+var metersPerDegree = radians(1) * earthRadiusMeters
+
+// offset returns the point northMeters north and eastMeters east of (0, 0).
+func offset(northMeters, eastMeters float64) Point {
+	return Point{Latitude: northMeters / metersPerDegree, Longitude: eastMeters / metersPerDegree}
+}
+
+func TestSimplify(t *testing.T) {
+	tests := []struct {
+		name      string
+		points    []Point
+		tolerance float64
+		want      []int
+	}{
+		{
+			name:      "too short to simplify",
+			points:    []Point{offset(0, 0), offset(100, 0)},
+			tolerance: 10,
+			want:      []int{0, 1},
+		},
+		{
+			name:      "collinear points are dropped",
+			points:    []Point{offset(0, 0), offset(100, 0), offset(200, 0), offset(300, 0)},
+			tolerance: 1,
+			want:      []int{0, 3},
+		},
+		{
+			name:      "deviation within tolerance is dropped",
+			points:    []Point{offset(0, 0), offset(100, 5), offset(200, 0)},
+			tolerance: 10,
+			want:      []int{0, 2},
+		},
+		{
+			name:      "deviation beyond tolerance is kept",
+			points:    []Point{offset(0, 0), offset(100, 50), offset(200, 0)},
+			tolerance: 10,
+			want:      []int{0, 1, 2},
+		},
+		{
+			name: "corners survive, noise between them does not",
+			points: []Point{
+				offset(0, 0), offset(50, 2), offset(100, 0),
+				offset(100, 50), offset(102, 100),
+			},
+			tolerance: 5,
+			want:      []int{0, 2, 4},
+		},
+		{
+			name:      "loop back to the start",
+			points:    []Point{offset(0, 0), offset(100, 0), offset(100, 100), offset(0, 100), offset(0, 0)},
+			tolerance: 1,
+			want:      []int{0, 1, 2, 3, 4},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			want := make([]Point, len(tc.want))
+			for i, index := range tc.want {
+				want[i] = tc.points[index]
+			}
+			if got := Simplify(tc.points, tc.tolerance); !reflect.DeepEqual(got, want) {
+				t.Errorf("Simplify() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestSimplifyWithinTolerance(t *testing.T) {
+	var points []Point
+	for i := 0; i <= 200; i++ {
+		points = append(points, offset(float64(i)*10, 30*math.Sin(float64(i)/10)))
+	}
+
+	const tolerance = 2.0
+	simplified := Simplify(points, tolerance)
+	if len(simplified) >= len(points) {
+		t.Fatalf("Simplify kept all %d points", len(points))
+	}
+
+	// Every dropped point must lie within tolerance of the simplified line.
+	// Near the equator the projection needs no longitude correction.
+	for _, p := range points {
+		nearest := math.Inf(1)
+		for i := 1; i < len(simplified); i++ {
+			a, b := simplified[i-1], simplified[i]
+			d := segmentDistance(
+				p.Longitude*metersPerDegree, p.Latitude*metersPerDegree,
+				a.Longitude*metersPerDegree, a.Latitude*metersPerDegree,
+				b.Longitude*metersPerDegree, b.Latitude*metersPerDegree,
+			)
+			nearest = math.Min(nearest, d)
+		}
+		if nearest > tolerance+1e-6 {
+			t.Errorf("point %v is %.2fm from the simplified line", p, nearest)
+		}
+	}
+}
+
+func TestStatsSegments(t *testing.T) {
+	track, err := Parse(strings.NewReader(`<?xml version="1.0"?>
+<gpx version="1.1" creator="test">
+  <trk><name>Two days</name>
+    <trkseg>
+      <trkpt lat="0" lon="0"><ele>10</ele><time>2026-10-01T08:00:00Z</time></trkpt>
+      <trkpt lat="0.01" lon="0"><ele>11</ele></trkpt>
+      <trkpt lat="0.02" lon="0"><ele>20</ele><time>2026-10-01T09:00:00Z</time></trkpt>
+    </trkseg>
+    <trkseg>
+      <trkpt lat="1" lon="1"><ele>5</ele><time>2026-10-02T08:00:00Z</time></trkpt>
+      <trkpt lat="1.01" lon="1"><ele>12</ele><time>2026-10-02T10:00:00Z</time></trkpt>
+    </trkseg>
+  </trk>
+</gpx>`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if track.Name != "Two days" || len(track.Segments) != 2 {
+		t.Fatalf("got %q with %d segments, want %q with 2", track.Name, len(track.Segments), "Two days")
+	}
+
+	stats := track.Stats()
+	if stats.PointCount != 5 {
+		t.Errorf("PointCount = %d, want 5", stats.PointCount)
+	}
+	// Three hops of 0.01° latitude; the jump between segments is not counted.
+	if want := 3 * 0.01 * metersPerDegree; math.Abs(stats.Distance-want) > 1 {
+		t.Errorf("Distance = %.1f, want %.1f", stats.Distance, want)
+	}
+	// The 1m rise is noise; each segment starts its own climb, so 10→20 and
+	// 5→12 count 10 and 7.
+	if stats.ElevationGain != 17 {
+		t.Errorf("ElevationGain = %v, want 17", stats.ElevationGain)
+	}
+	if stats.Duration != 26*time.Hour {
+		t.Errorf("Duration = %v, want 26h", stats.Duration)
+	}
+	if stats.Bounds != (Bounds{MinLatitude: 0, MinLongitude: 0, MaxLatitude: 1.01, MaxLongitude: 1}) {
+		t.Errorf("Bounds = %+v", stats.Bounds)
+	}
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"travel-blog-backend/internal/database"
+	"travel-blog-backend/internal/models"
+	"travel-blog-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// attachmentOwner identifies the post or trip that gallery images and tracks
+// are attached to. model is the owning row, used to serialize changes.
+type attachmentOwner struct {
+	column string
+	id     uint
+	model  interface{}
+}
+
+func postOwner(id uint) attachmentOwner {
+	return attachmentOwner{column: "post_id", id: id, model: &models.Post{}}
+}
+
+func tripOwner(id uint) attachmentOwner {
+	return attachmentOwner{column: "trip_id", id: id, model: &models.Trip{}}
+}
+
+func (o attachmentOwner) scope(db *gorm.DB) *gorm.DB {
+	return db.Where(o.column+" = ?", o.id)
+}
+
+// ids returns the owner as the post and trip foreign keys of an attachment.
+func (o attachmentOwner) ids() (postID, tripID *uint) {
+	id := o.id
+	if o.column == "post_id" {
+		return &id, nil
+	}
+	return nil, &id
+}
+
+// lock takes a row lock on the owner so concurrent changes to its
+// attachments are applied one at a time.
+func (o attachmentOwner) lock(tx *gorm.DB) error {
+	return tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(o.model, o.id).Error
+}
+
+// postAttachmentOwner resolves the :id post. With write set, the current
+// user must also be allowed to edit it; otherwise they must be allowed to
+// see it.
+func postAttachmentOwner(c *gin.Context, write bool) (attachmentOwner, bool) {
+	id, ok := utils.ParseUintParam(c, "id", "Invalid post ID")
+	if !ok {
+		return attachmentOwner{}, false
+	}
+
+	var post models.Post
+	if err := database.DB.Select(postVisibilityColumns).First(&post, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("post not found"))
+		} else {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get post"))
+		}
+		return attachmentOwner{}, false
+	}
+
+	if write && !checkPostPermission(c, post) {
+		return attachmentOwner{}, false
+	}
+	if !write && !checkPostVisible(c, post) {
+		return attachmentOwner{}, false
+	}
+	return postOwner(post.ID), true
+}
+
+// tripAttachmentOwner resolves the :id trip. With write set, the current
+// user must also be allowed to edit it.
+func tripAttachmentOwner(c *gin.Context, write bool) (attachmentOwner, bool) {
+	id, ok := utils.ParseUintParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return attachmentOwner{}, false
+	}
+
+	var trip models.Trip
+	if err := database.DB.Select("id", "user_id").First(&trip, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("trip not found"))
+		} else {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get trip"))
+		}
+		return attachmentOwner{}, false
+	}
+
+	if write && !checkTripPermission(c, trip) {
+		return attachmentOwner{}, false
+	}
+	return tripOwner(trip.ID), true
+}
+
+// attachmentOwnerOf resolves the owner of an existing attachment and checks
+// that the current user may edit it. Posts in the trash still count.
+func attachmentOwnerOf(c *gin.Context, postID, tripID *uint) (attachmentOwner, bool) {
+	if postID != nil {
+		var post models.Post
+		if err := database.DB.Unscoped().Select("id", "user_id").First(&post, *postID).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get post"))
+			return attachmentOwner{}, false
+		}
+		if !checkPostPermission(c, post) {
+			return attachmentOwner{}, false
+		}
+		return postOwner(post.ID), true
+	}
+
+	var trip models.Trip
+	if err := database.DB.Select("id", "user_id").First(&trip, *tripID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get trip"))
+		return attachmentOwner{}, false
+	}
+	if !checkTripPermission(c, trip) {
+		return attachmentOwner{}, false
+	}
+	return tripOwner(trip.ID), true
+}
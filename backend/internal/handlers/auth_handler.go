@@ -106,6 +106,11 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
+	if user.Suspended(time.Now()) {
+		c.JSON(http.StatusForbidden, utils.ErrorResponse("account suspended"))
+		return
+	}
+
 	accessToken, refreshToken, err := h.generateTokens(user.ID, user.Email, string(user.Role))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to generate tokens"))
@@ -0,0 +1,333 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"travel-blog-backend/internal/database"
+	"travel-blog-backend/internal/models"
+	"travel-blog-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BookmarkHandler struct{}
+
+func NewBookmarkHandler() *BookmarkHandler {
+	return &BookmarkHandler{}
+}
+
+type AddBookmarkRequest struct {
+	CollectionID *uint `json:"collectionId"`
+}
+
+type CollectionRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+type BookmarkListResponse struct {
+	Bookmarks  []models.Bookmark `json:"bookmarks"`
+	Total      int64             `json:"total"`
+	Page       int               `json:"page"`
+	PageSize   int               `json:"pageSize"`
+	TotalPages int               `json:"totalPages"`
+}
+
+// attachBookmarkFlags marks which posts the signed-in reader has bookmarked.
+// Anonymous requests are left untouched so the flag is omitted.
+func attachBookmarkFlags(c *gin.Context, posts []models.Post) error {
+	userID, exists := c.Get("userID")
+	if !exists || len(posts) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(posts))
+	for i := range posts {
+		ids[i] = posts[i].ID
+	}
+
+	var bookmarked []uint
+	if err := database.DB.Model(&models.Bookmark{}).
+		Where("user_id = ? AND post_id IN ?", userID.(uint), ids).
+		Pluck("post_id", &bookmarked).Error; err != nil {
+		return err
+	}
+
+	set := make(map[uint]bool, len(bookmarked))
+	for _, id := range bookmarked {
+		set[id] = true
+	}
+	for i := range posts {
+		flag := set[posts[i].ID]
+		posts[i].IsBookmarked = &flag
+	}
+	return nil
+}
+
+func (h *BookmarkHandler) findCollection(c *gin.Context, id uint, collection *models.BookmarkCollection) bool {
+	userID, _ := c.Get("userID")
+	if err := database.DB.Where("user_id = ?", userID.(uint)).First(collection, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("collection not found"))
+		} else {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get collection"))
+		}
+		return false
+	}
+	return true
+}
+
+// checkCollectionName rejects a name already used by another of the user's
+// collections, ignoring case.
+func (h *BookmarkHandler) checkCollectionName(c *gin.Context, name string, exceptID uint) bool {
+	userID, _ := c.Get("userID")
+
+	var count int64
+	if err := database.DB.Model(&models.BookmarkCollection{}).
+		Where("user_id = ? AND LOWER(name) = LOWER(?) AND id <> ?", userID.(uint), name, exceptID).
+		Count(&count).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to check collection"))
+		return false
+	}
+	if count > 0 {
+		c.JSON(http.StatusConflict, utils.ErrorResponse("collection with this name already exists"))
+		return false
+	}
+	return true
+}
+
+// GetBookmarks lists the current user's bookmarks, newest first. Pass
+// collectionId to list a single collection, or collectionId=none for
+// bookmarks outside any collection.
+func (h *BookmarkHandler) GetBookmarks(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	page, pageSize := utils.ParsePagination(c, 1, 20, 100)
+
+	// Bookmarks of posts the user can no longer see are kept but hidden.
+	visible := database.DB.Model(&models.Post{}).Select("posts.id").Scopes(visiblePostsScope(c, false))
+	query := database.DB.Model(&models.Bookmark{}).
+		Where("user_id = ? AND post_id IN (?)", userID.(uint), visible)
+
+	switch value := c.Query("collectionId"); value {
+	case "":
+	case "none":
+		query = query.Where("collection_id IS NULL")
+	default:
+		collectionID, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid collection ID"))
+			return
+		}
+		query = query.Where("collection_id = ?", collectionID)
+	}
+
+	var bookmarks []models.Bookmark
+	var total int64
+
+	query.Count(&total)
+
+	offset := (page - 1) * pageSize
+	if err := query.Preload("Post").
+		Preload("Post.User").
+		Order("created_at DESC, id DESC").
+		Limit(pageSize).
+		Offset(offset).
+		Find(&bookmarks).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get bookmarks"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(BookmarkListResponse{
+		Bookmarks:  bookmarks,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: utils.CalculateTotalPages(total, pageSize),
+	}))
+}
+
+// AddBookmark saves a post for the current user. Bookmarking the same post
+// again only moves it to the given collection.
+func (h *BookmarkHandler) AddBookmark(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	postID, ok := utils.ParseUintParam(c, "postId", "Invalid post ID")
+	if !ok {
+		return
+	}
+
+	// The body is optional; without one the bookmark is uncategorized.
+	var req AddBookmarkRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request data: "+err.Error()))
+		return
+	}
+
+	var post models.Post
+	if err := database.DB.Select(postVisibilityColumns).First(&post, postID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("post not found"))
+		} else {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get post"))
+		}
+		return
+	}
+	if !checkPostVisible(c, post) {
+		return
+	}
+	if post.Status != models.StatusPublished {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("only published posts can be bookmarked"))
+		return
+	}
+
+	if req.CollectionID != nil {
+		var collection models.BookmarkCollection
+		if !h.findCollection(c, *req.CollectionID, &collection) {
+			return
+		}
+	}
+
+	bookmark := models.Bookmark{
+		UserID:       userID.(uint),
+		PostID:       post.ID,
+		CollectionID: req.CollectionID,
+	}
+	if err := database.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "post_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"collection_id"}),
+	}).Create(&bookmark).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to save bookmark"))
+		return
+	}
+
+	database.DB.Preload("Post").Preload("Post.User").
+		Where("user_id = ? AND post_id = ?", bookmark.UserID, bookmark.PostID).
+		First(&bookmark)
+	c.JSON(http.StatusOK, utils.SuccessResponse(bookmark))
+}
+
+// RemoveBookmark deletes the current user's bookmark of a post. Removing a
+// bookmark that does not exist is a no-op.
+func (h *BookmarkHandler) RemoveBookmark(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	postID, ok := utils.ParseUintParam(c, "postId", "Invalid post ID")
+	if !ok {
+		return
+	}
+
+	if err := database.DB.
+		Where("user_id = ? AND post_id = ?", userID.(uint), postID).
+		Delete(&models.Bookmark{}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to remove bookmark"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.MessageResponse("Bookmark removed"))
+}
+
+func (h *BookmarkHandler) GetCollections(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	collections := make([]models.BookmarkCollection, 0)
+	if err := database.DB.Model(&models.BookmarkCollection{}).
+		Select(`bookmark_collections.*, (
+			SELECT COUNT(*) FROM bookmarks
+			JOIN posts ON posts.id = bookmarks.post_id AND posts.deleted_at IS NULL
+			WHERE bookmarks.collection_id = bookmark_collections.id
+		) AS bookmarks_count`).
+		Where("user_id = ?", userID.(uint)).
+		Order("LOWER(name) ASC").
+		Find(&collections).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get collections"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(collections))
+}
+
+func (h *BookmarkHandler) CreateCollection(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req CollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request data: "+err.Error()))
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("name must not be empty"))
+		return
+	}
+	if !h.checkCollectionName(c, name, 0) {
+		return
+	}
+
+	collection := models.BookmarkCollection{UserID: userID.(uint), Name: name}
+	if err := database.DB.Create(&collection).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to create collection"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.SuccessResponse(collection))
+}
+
+func (h *BookmarkHandler) UpdateCollection(c *gin.Context) {
+	id, ok := utils.ParseUintParam(c, "id", "Invalid collection ID")
+	if !ok {
+		return
+	}
+
+	var req CollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request data: "+err.Error()))
+		return
+	}
+
+	var collection models.BookmarkCollection
+	if !h.findCollection(c, id, &collection) {
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("name must not be empty"))
+		return
+	}
+	if !h.checkCollectionName(c, name, collection.ID) {
+		return
+	}
+
+	if err := database.DB.Model(&collection).Update("name", name).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to update collection"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(collection))
+}
+
+// DeleteCollection removes a collection. Its bookmarks are kept and become
+// uncategorized.
+func (h *BookmarkHandler) DeleteCollection(c *gin.Context) {
+	id, ok := utils.ParseUintParam(c, "id", "Invalid collection ID")
+	if !ok {
+		return
+	}
+
+	var collection models.BookmarkCollection
+	if !h.findCollection(c, id, &collection) {
+		return
+	}
+
+	if err := database.DB.Delete(&collection).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to delete collection"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.MessageResponse("Collection deleted successfully"))
+}
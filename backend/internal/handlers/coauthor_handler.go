@@ -0,0 +1,340 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+	"travel-blog-backend/internal/database"
+	"travel-blog-backend/internal/models"
+	"travel-blog-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// authoredPostsSQL matches the posts the user given as its argument is an
+// accepted author of, whatever their role.
+const authoredPostsSQL = "posts.id IN (SELECT post_id FROM post_authors WHERE user_id = ? AND accepted_at IS NOT NULL)"
+
+// ownedPostsSQL is authoredPostsSQL restricted to the owner role.
+const ownedPostsSQL = "posts.id IN (SELECT post_id FROM post_authors WHERE user_id = ? AND role = 'owner' AND accepted_at IS NOT NULL)"
+
+type CoAuthorHandler struct{}
+
+func NewCoAuthorHandler() *CoAuthorHandler {
+	return &CoAuthorHandler{}
+}
+
+type InviteAuthorRequest struct {
+	Username string `json:"username" binding:"required"`
+	Role     string `json:"role"`
+}
+
+type UpdateAuthorRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+func parsePostAuthorRole(value string) (models.PostAuthorRole, bool) {
+	switch models.PostAuthorRole(value) {
+	case "", models.PostAuthorEditor:
+		return models.PostAuthorEditor, true
+	case models.PostAuthorOwner:
+		return models.PostAuthorOwner, true
+	}
+	return "", false
+}
+
+// postAuthorRole returns the role userID holds on post, or an empty role if
+// they are not an accepted author.
+func postAuthorRole(post models.Post, userID uint) (models.PostAuthorRole, error) {
+	if post.UserID == userID {
+		return models.PostAuthorOwner, nil
+	}
+
+	var authors []models.PostAuthor
+	if err := database.DB.
+		Where("post_id = ? AND user_id = ? AND accepted_at IS NOT NULL", post.ID, userID).
+		Limit(1).
+		Find(&authors).Error; err != nil {
+		return "", err
+	}
+	if len(authors) == 0 {
+		return "", nil
+	}
+	return authors[0].Role, nil
+}
+
+// attachAuthors fills in the accepted authors of every post, owners first.
+func attachAuthors(posts []models.Post) error {
+	if len(posts) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(posts))
+	index := make(map[uint]int, len(posts))
+	for i := range posts {
+		ids[i] = posts[i].ID
+		index[posts[i].ID] = i
+		posts[i].Authors = make([]models.PostAuthor, 0, 1)
+	}
+
+	var authors []models.PostAuthor
+	if err := database.DB.Preload("User").
+		Where("post_id IN ? AND accepted_at IS NOT NULL", ids).
+		Order("role = 'owner' DESC, accepted_at ASC").
+		Find(&authors).Error; err != nil {
+		return err
+	}
+
+	for _, author := range authors {
+		i := index[author.PostID]
+		posts[i].Authors = append(posts[i].Authors, author)
+	}
+	return nil
+}
+
+func (h *CoAuthorHandler) findPost(c *gin.Context, post *models.Post) bool {
+	id, ok := utils.ParseUintParam(c, "id", "Invalid post ID")
+	if !ok {
+		return false
+	}
+
+	if err := database.DB.Select(postVisibilityColumns).First(post, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("post not found"))
+		} else {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get post"))
+		}
+		return false
+	}
+	return true
+}
+
+func (h *CoAuthorHandler) findAuthor(c *gin.Context, postID uint, author *models.PostAuthor) bool {
+	userID, ok := utils.ParseUintParam(c, "userId", "Invalid user ID")
+	if !ok {
+		return false
+	}
+
+	if err := database.DB.Where("post_id = ? AND user_id = ?", postID, userID).First(author).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("author not found"))
+		} else {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get author"))
+		}
+		return false
+	}
+	return true
+}
+
+// GetAuthors lists the authors of a post. Its own authors and admins also see
+// pending invitations.
+func (h *CoAuthorHandler) GetAuthors(c *gin.Context) {
+	var post models.Post
+	if !h.findPost(c, &post) || !checkPostVisible(c, post) {
+		return
+	}
+
+	withPending := false
+	if userID, exists := c.Get("userID"); exists {
+		userRole, _ := c.Get("userRole")
+		role, err := postAuthorRole(post, userID.(uint))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get authors"))
+			return
+		}
+		withPending = role != "" || userRole.(string) == "admin"
+	}
+
+	query := database.DB.Preload("User").Where("post_id = ?", post.ID)
+	if !withPending {
+		query = query.Where("accepted_at IS NOT NULL")
+	}
+
+	authors := make([]models.PostAuthor, 0)
+	if err := query.Order("accepted_at IS NULL, role = 'owner' DESC, accepted_at ASC, created_at ASC").
+		Find(&authors).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get authors"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(authors))
+}
+
+// InviteAuthor invites a user to co-author a post. The invitation counts once
+// the user accepts it.
+func (h *CoAuthorHandler) InviteAuthor(c *gin.Context) {
+	var req InviteAuthorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request data: "+err.Error()))
+		return
+	}
+
+	role, valid := parsePostAuthorRole(req.Role)
+	if !valid {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("role must be owner or editor"))
+		return
+	}
+
+	var post models.Post
+	if !h.findPost(c, &post) || !checkPostOwnership(c, post) {
+		return
+	}
+
+	var invitee models.User
+	if err := database.DB.Select("id").Where("username = ?", strings.TrimSpace(req.Username)).First(&invitee).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("user not found"))
+		} else {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get user"))
+		}
+		return
+	}
+
+	var existing int64
+	if err := database.DB.Model(&models.PostAuthor{}).
+		Where("post_id = ? AND user_id = ?", post.ID, invitee.ID).
+		Count(&existing).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to invite author"))
+		return
+	}
+	if existing > 0 {
+		c.JSON(http.StatusConflict, utils.ErrorResponse("user is already an author or invited"))
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	inviterID := userID.(uint)
+	author := models.PostAuthor{
+		PostID:    post.ID,
+		UserID:    invitee.ID,
+		Role:      role,
+		InvitedBy: &inviterID,
+	}
+	if err := database.DB.Create(&author).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to invite author"))
+		return
+	}
+
+	database.DB.Preload("User").Where("post_id = ? AND user_id = ?", author.PostID, author.UserID).First(&author)
+	c.JSON(http.StatusCreated, utils.SuccessResponse(author))
+}
+
+// AcceptInvitation makes the current user an author of a post they were
+// invited to.
+func (h *CoAuthorHandler) AcceptInvitation(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var post models.Post
+	if !h.findPost(c, &post) {
+		return
+	}
+
+	result := database.DB.Model(&models.PostAuthor{}).
+		Where("post_id = ? AND user_id = ? AND accepted_at IS NULL", post.ID, userID.(uint)).
+		Update("accepted_at", time.Now())
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to accept invitation"))
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("invitation not found"))
+		return
+	}
+
+	var author models.PostAuthor
+	database.DB.Preload("User").Where("post_id = ? AND user_id = ?", post.ID, userID.(uint)).First(&author)
+	c.JSON(http.StatusOK, utils.SuccessResponse(author))
+}
+
+// UpdateAuthor changes the role of an author or pending invitation. The
+// post's creator always stays an owner.
+func (h *CoAuthorHandler) UpdateAuthor(c *gin.Context) {
+	var req UpdateAuthorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request data: "+err.Error()))
+		return
+	}
+
+	role, valid := parsePostAuthorRole(req.Role)
+	if !valid {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("role must be owner or editor"))
+		return
+	}
+
+	var post models.Post
+	if !h.findPost(c, &post) || !checkPostOwnership(c, post) {
+		return
+	}
+
+	var author models.PostAuthor
+	if !h.findAuthor(c, post.ID, &author) {
+		return
+	}
+	if author.UserID == post.UserID && role != models.PostAuthorOwner {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("the post's creator must remain an owner"))
+		return
+	}
+
+	if err := database.DB.Model(&author).Update("role", role).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to update author"))
+		return
+	}
+
+	database.DB.Preload("User").Where("post_id = ? AND user_id = ?", author.PostID, author.UserID).First(&author)
+	c.JSON(http.StatusOK, utils.SuccessResponse(author))
+}
+
+// RemoveAuthor removes an author or withdraws an invitation. Owners may
+// remove anyone but the post's creator; other users may only remove
+// themselves, which also declines a pending invitation.
+func (h *CoAuthorHandler) RemoveAuthor(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var post models.Post
+	if !h.findPost(c, &post) {
+		return
+	}
+
+	var author models.PostAuthor
+	if !h.findAuthor(c, post.ID, &author) {
+		return
+	}
+	if author.UserID == post.UserID {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("the post's creator cannot be removed"))
+		return
+	}
+	if author.UserID != userID.(uint) && !checkPostOwnership(c, post) {
+		return
+	}
+
+	if err := database.DB.
+		Where("post_id = ? AND user_id = ?", author.PostID, author.UserID).
+		Delete(&models.PostAuthor{}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to remove author"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.MessageResponse("Author removed"))
+}
+
+// GetInvitations lists the current user's pending co-author invitations,
+// newest first.
+func (h *CoAuthorHandler) GetInvitations(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	invitations := make([]models.PostAuthor, 0)
+	if err := database.DB.
+		Preload("Post").
+		Preload("Post.User").
+		Where("user_id = ? AND accepted_at IS NULL", userID.(uint)).
+		Where("post_id IN (SELECT id FROM posts WHERE deleted_at IS NULL)").
+		Order("created_at DESC").
+		Find(&invitations).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get invitations"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(invitations))
+}
@@ -2,19 +2,26 @@ package handlers
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
+	"time"
+	"travel-blog-backend/internal/config"
 	"travel-blog-backend/internal/database"
 	"travel-blog-backend/internal/models"
+	"travel-blog-backend/internal/spam"
 	"travel-blog-backend/internal/utils"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-type CommentHandler struct{}
+type CommentHandler struct {
+	spam *spam.Pipeline
+}
 
-func NewCommentHandler() *CommentHandler {
-	return &CommentHandler{}
+func NewCommentHandler(filter *spam.Pipeline) *CommentHandler {
+	return &CommentHandler{spam: filter}
 }
 
 type CreateCommentRequest struct {
@@ -22,17 +29,68 @@ type CreateCommentRequest struct {
 	ParentID *uint  `json:"parentId"`
 }
 
+type UpdateCommentRequest struct {
+	Content string `json:"content" binding:"required"`
+}
+
+// deletedCommentContent and removedCommentContent replace the content of
+// deleted and moderated comments that are still listed because they have
+// replies.
+const (
+	deletedCommentContent = "[deleted]"
+	removedCommentContent = "[removed]"
+)
+
+// shownCommentSQL matches the comments of a table shown to the @viewer user:
+// approved ones, and their own comments still awaiting moderation. Comments
+// hidden after being reported are shown to no one.
+const shownCommentSQL = `%[1]s.deleted_at IS NULL AND %[1]s.hidden_at IS NULL AND (%[1]s.status = 'approved' OR (%[1]s.status = 'pending' AND %[1]s.user_id = @viewer))`
+
+// commentThreadSQL matches comments that are shown, or that are hidden but
+// still have shown replies somewhere below them and are kept as
+// placeholders.
+var commentThreadSQL = fmt.Sprintf(`%s OR EXISTS (
+	SELECT 1 FROM comments descendants
+	WHERE descendants.path LIKE comments.path || '_%%' AND %s
+)`, fmt.Sprintf(shownCommentSQL, "comments"), fmt.Sprintf(shownCommentSQL, "descendants"))
+
+// commentViewer returns the ID of the signed-in user, or 0 for anonymous
+// readers.
+func commentViewer(c *gin.Context) uint {
+	if userID, exists := c.Get("userID"); exists {
+		return userID.(uint)
+	}
+	return 0
+}
+
+// visibleComments limits an unscoped comment query to the comments listed
+// in threads for the current user, placeholders included.
+func visibleComments(c *gin.Context, query *gorm.DB) *gorm.DB {
+	return query.Where(commentThreadSQL, map[string]interface{}{"viewer": commentViewer(c)})
+}
+
+// commentShown mirrors shownCommentSQL for a loaded comment.
+func commentShown(comment models.Comment, viewer uint) bool {
+	if comment.DeletedAt.Valid || comment.HiddenAt != nil {
+		return false
+	}
+	return comment.Status == models.CommentApproved ||
+		(comment.Status == models.CommentPending && comment.UserID == viewer)
+}
+
 type CommentListResponse struct {
 	Comments   []models.Comment `json:"comments"`
 	Total      int64            `json:"total"`
-	Page       int              `json:"page"`
+	Page       int              `json:"page,omitempty"`
 	PageSize   int              `json:"pageSize"`
 	TotalPages int              `json:"totalPages"`
+	NextCursor *string          `json:"nextCursor,omitempty"`
+	PrevCursor *string          `json:"prevCursor,omitempty"`
 }
 
 func (h *CommentHandler) GetCommentsCount(c *gin.Context) {
 	var total int64
-	if err := database.DB.Model(&models.Comment{}).Count(&total).Error; err != nil {
+	if err := database.DB.Model(&models.Comment{}).Where("status = ?", models.CommentApproved).Count(&total).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to count comments"))
 		return
 	}
@@ -40,6 +98,32 @@ func (h *CommentHandler) GetCommentsCount(c *gin.Context) {
 	c.JSON(http.StatusOK, utils.SuccessResponse(gin.H{"total": total}))
 }
 
+// maskHiddenComments turns comments the viewer may not see into "[deleted]"
+// or "[removed]" placeholders that keep their place in the thread but not
+// their content or author.
+func maskHiddenComments(comments []models.Comment, viewer uint) {
+	for i := range comments {
+		if !commentShown(comments[i], viewer) {
+			comments[i].Content = removedCommentContent
+			if comments[i].DeletedAt.Valid {
+				comments[i].Content = deletedCommentContent
+			}
+			comments[i].UserID = 0
+			comments[i].User = models.User{}
+			comments[i].EditedAt = nil
+			comments[i].ModeratedBy = nil
+			comments[i].ModeratedAt = nil
+		}
+		maskHiddenComments(comments[i].Replies, viewer)
+	}
+}
+
+// GetComments lists the top-level comments of a post with the first replies
+// of every branch of their threads. Without a sort, comments are listed
+// newest first and replies oldest first. Only approved comments and the
+// reader's own pending ones are shown; hidden comments that have replies are
+// listed as placeholders. Passing a cursor switches from page numbers to
+// keyset pagination, which only supports the oldest and newest sorts.
 func (h *CommentHandler) GetComments(c *gin.Context) {
 	postID, ok := utils.ParseUintParam(c, "postId", "Invalid post ID")
 	if !ok {
@@ -48,34 +132,81 @@ func (h *CommentHandler) GetComments(c *gin.Context) {
 
 	page, pageSize := utils.ParsePagination(c, 1, 10, 100)
 
+	keyset, cursorMode, ok := parseKeysetRequest(c, pageSize)
+	if !ok {
+		return
+	}
+
+	sortMode, ok := parseCommentSort(c, commentSortNewest)
+	if !ok {
+		return
+	}
+	replySort := sortMode
+	if c.Query("sort") == "" {
+		replySort = commentSortOldest
+	}
+
+	if cursorMode && c.Query("sort") != "" {
+		if sortMode == commentSortTop {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("cursor pagination only supports sort=oldest and sort=newest"))
+			return
+		}
+		keyset.desc = sortMode == commentSortNewest
+	}
+
+	if !checkPostIDVisible(c, postID) {
+		return
+	}
+
 	var comments []models.Comment
 	var total int64
 
-	query := database.DB.Model(&models.Comment{}).Where("post_id = ? AND parent_id IS NULL", postID)
+	query := visibleComments(c, database.DB.Unscoped().Model(&models.Comment{}).
+		Where("comments.post_id = ? AND comments.parent_id IS NULL", postID))
 	query.Count(&total)
 
-	offset := (page - 1) * pageSize
-	if err := query.Preload("User").
-		Preload("Replies", func(db *gorm.DB) *gorm.DB {
-			return db.Preload("User").Order("created_at ASC")
-		}).
-		Order("created_at DESC").
-		Limit(pageSize).
-		Offset(offset).
-		Find(&comments).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get comments"))
-		return
-	}
+	query = query.Preload("User")
 
-	c.JSON(http.StatusOK, utils.SuccessResponse(CommentListResponse{
-		Comments:   comments,
+	response := CommentListResponse{
 		Total:      total,
-		Page:       page,
 		PageSize:   pageSize,
 		TotalPages: utils.CalculateTotalPages(total, pageSize),
-	}))
+	}
+	if cursorMode {
+		next, prev, err := keysetPage(query, "comments", keyset, &comments, func(comment models.Comment) (time.Time, uint) {
+			return comment.CreatedAt, comment.ID
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get comments"))
+			return
+		}
+		response.NextCursor, response.PrevCursor = next, prev
+	} else {
+		offset := (page - 1) * pageSize
+		if err := query.
+			Order(commentOrders[sortMode]).
+			Limit(pageSize).
+			Offset(offset).
+			Find(&comments).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get comments"))
+			return
+		}
+		response.Page = page
+	}
+
+	if err := loadCommentThreads(c, comments, replySort); err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get comments"))
+		return
+	}
+
+	maskHiddenComments(comments, commentViewer(c))
+	response.Comments = comments
+	c.JSON(http.StatusOK, utils.SuccessResponse(response))
 }
 
+// CreateComment adds a comment to a post. Depending on its spam score and
+// the post's moderation mode it goes live at once, waits, pending, for a
+// moderator, or is refused.
 func (h *CommentHandler) CreateComment(c *gin.Context) {
 	postID, ok := utils.ParseUintParam(c, "postId", "Invalid post ID")
 	if !ok {
@@ -103,19 +234,288 @@ func (h *CommentHandler) CreateComment(c *gin.Context) {
 		}
 		return
 	}
+	if !checkPostVisible(c, post) {
+		return
+	}
+
+	var parent *models.Comment
+	if req.ParentID != nil {
+		parent = &models.Comment{}
+		if err := database.DB.Where("post_id = ? AND status = ?", postID, models.CommentApproved).First(parent, *req.ParentID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusBadRequest, utils.ErrorResponse("parent comment not found on this post"))
+			} else {
+				c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get parent comment"))
+			}
+			return
+		}
+		if parent.Depth >= config.AppConfig.CommentMaxDepth {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse(fmt.Sprintf("replies can be nested at most %d levels deep", config.AppConfig.CommentMaxDepth)))
+			return
+		}
+	}
+
+	status, screening, err := h.screenComment(c, post, req.Content)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to create comment"))
+		return
+	}
 
 	comment := models.Comment{
-		Content:  req.Content,
-		PostID:   postID,
-		UserID:   userID.(uint),
-		ParentID: req.ParentID,
+		Content:     req.Content,
+		PostID:      postID,
+		UserID:      userID.(uint),
+		ParentID:    req.ParentID,
+		Status:      status,
+		SpamScore:   screening.Score,
+		SpamReasons: screening.Reasons(),
+	}
+	if parent != nil {
+		comment.Depth = parent.Depth + 1
 	}
 
-	if err := database.DB.Create(&comment).Error; err != nil {
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&comment).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&comment).Update("path", commentPath(parent, comment.ID)).Error; err != nil {
+			return err
+		}
+		if status == models.CommentPending {
+			return notifyPendingComment(tx, post, comment)
+		}
+		return nil
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to create comment"))
 		return
 	}
 
+	// Refused comments are kept as spam so moderators can still rescue
+	// false positives from the queue.
+	if status == models.CommentSpam {
+		c.JSON(http.StatusUnprocessableEntity, utils.ErrorResponse("comment was rejected as spam"))
+		return
+	}
+
 	database.DB.Preload("User").Preload("Post").First(&comment, comment.ID)
 	c.JSON(http.StatusCreated, utils.SuccessResponse(comment))
 }
+
+// GetReplies lists a page of the direct replies to a comment, oldest first
+// unless another sort is given, each with the first replies of its own
+// branches.
+func (h *CommentHandler) GetReplies(c *gin.Context) {
+	id, ok := utils.ParseUintParam(c, "id", "Invalid comment ID")
+	if !ok {
+		return
+	}
+
+	page, pageSize := utils.ParsePagination(c, 1, 10, 100)
+
+	sortMode, ok := parseCommentSort(c, commentSortOldest)
+	if !ok {
+		return
+	}
+
+	var parent models.Comment
+	if err := visibleComments(c, database.DB.Unscoped()).First(&parent, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("comment not found"))
+		} else {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get comment"))
+		}
+		return
+	}
+
+	if !checkPostIDVisible(c, parent.PostID) {
+		return
+	}
+
+	var replies []models.Comment
+	var total int64
+
+	query := visibleComments(c, database.DB.Unscoped().Model(&models.Comment{}).
+		Where("comments.parent_id = ?", parent.ID))
+	query.Count(&total)
+
+	offset := (page - 1) * pageSize
+	if err := query.Preload("User").
+		Order(commentOrders[sortMode]).
+		Limit(pageSize).
+		Offset(offset).
+		Find(&replies).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get replies"))
+		return
+	}
+
+	if err := loadCommentThreads(c, replies, sortMode); err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get replies"))
+		return
+	}
+
+	maskHiddenComments(replies, commentViewer(c))
+	c.JSON(http.StatusOK, utils.SuccessResponse(CommentListResponse{
+		Comments:   replies,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: utils.CalculateTotalPages(total, pageSize),
+	}))
+}
+
+func (h *CommentHandler) findComment(c *gin.Context, comment *models.Comment) bool {
+	id, ok := utils.ParseUintParam(c, "id", "Invalid comment ID")
+	if !ok {
+		return false
+	}
+
+	if err := database.DB.First(comment, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("comment not found"))
+		} else {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get comment"))
+		}
+		return false
+	}
+	return true
+}
+
+// UpdateComment replaces the content of a comment, keeping the previous
+// content in its edit history. The new content is screened like a new
+// comment, which may send the comment back to moderation or to spam.
+func (h *CommentHandler) UpdateComment(c *gin.Context) {
+	var req UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request data: "+err.Error()))
+		return
+	}
+
+	var comment models.Comment
+	if !h.findComment(c, &comment) || !checkCommentPermission(c, comment) {
+		return
+	}
+
+	if req.Content != comment.Content {
+		var post models.Post
+		if err := database.DB.Unscoped().Select("id", "user_id", "comment_moderation").First(&post, comment.PostID).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to update comment"))
+			return
+		}
+
+		// Edits are screened like new comments, so an approved comment
+		// cannot be turned into spam after the fact.
+		screened, screening, err := h.screenComment(c, post, req.Content)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to update comment"))
+			return
+		}
+		status := editedCommentStatus(comment.Status, screened)
+
+		userID, _ := c.Get("userID")
+		updates := map[string]interface{}{
+			"content":   req.Content,
+			"edited_at": time.Now(),
+			"status":    status,
+		}
+		if screening.Verdict != "" {
+			updates["spam_score"] = screening.Score
+			updates["spam_reasons"] = models.StringList(screening.Reasons())
+		}
+		err = database.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(&models.CommentRevision{
+				CommentID: comment.ID,
+				UserID:    userID.(uint),
+				Content:   comment.Content,
+			}).Error; err != nil {
+				return err
+			}
+
+			// The classifier learnt from the old content; take that back so a
+			// later moderation decision trains it on what is there now. The
+			// row is locked so a concurrent moderation cannot train in between.
+			var trained models.Comment
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Select("id", "content", "spam_trained").
+				First(&trained, comment.ID).Error; err != nil {
+				return err
+			}
+			if trained.SpamTrained != nil {
+				if err := spam.Learn(tx, trained.Content, *trained.SpamTrained, -1); err != nil {
+					return err
+				}
+				updates["spam_trained"] = nil
+			}
+
+			if err := tx.Model(&comment).Updates(updates).Error; err != nil {
+				return err
+			}
+			if status == models.CommentPending && comment.Status != models.CommentPending {
+				return notifyPendingComment(tx, post, comment)
+			}
+			return nil
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to update comment"))
+			return
+		}
+
+		if status == models.CommentSpam {
+			c.JSON(http.StatusUnprocessableEntity, utils.ErrorResponse("comment was rejected as spam"))
+			return
+		}
+	}
+
+	database.DB.Preload("User").First(&comment, comment.ID)
+	c.JSON(http.StatusOK, utils.SuccessResponse(comment))
+}
+
+// DeleteComment moves a comment to the trash. Its replies stay visible
+// under a "[deleted]" placeholder.
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	var comment models.Comment
+	if !h.findComment(c, &comment) || !checkCommentPermission(c, comment) {
+		return
+	}
+
+	if err := database.DB.Delete(&comment).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to delete comment"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.MessageResponse("Comment deleted successfully"))
+}
+
+// GetCommentHistory lists the earlier versions of a comment, newest first.
+// Whoever may moderate the comment may see it, including for comments in the
+// trash; other comments are reported as not found.
+func (h *CommentHandler) GetCommentHistory(c *gin.Context) {
+	id, ok := utils.ParseUintParam(c, "id", "Invalid comment ID")
+	if !ok {
+		return
+	}
+
+	var comment models.Comment
+	if err := moderationScope(c, database.DB.Unscoped().Model(&models.Comment{})).
+		Select("comments.id").
+		Where("comments.id = ?", id).
+		First(&comment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("comment not found"))
+		} else {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get comment"))
+		}
+		return
+	}
+
+	revisions := make([]models.CommentRevision, 0)
+	if err := database.DB.Preload("User").
+		Where("comment_id = ?", comment.ID).
+		Order("created_at DESC, id DESC").
+		Find(&revisions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get comment history"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(revisions))
+}
@@ -0,0 +1,340 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+	"travel-blog-backend/internal/config"
+	"travel-blog-backend/internal/database"
+	"travel-blog-backend/internal/models"
+	"travel-blog-backend/internal/spam"
+	"travel-blog-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// moderatedPostsSQL limits comments to those on posts the user is an
+// accepted author of.
+const moderatedPostsSQL = "comments.post_id IN (SELECT post_id FROM post_authors WHERE user_id = ? AND accepted_at IS NOT NULL)"
+
+type ModerateCommentsRequest struct {
+	CommentIDs []uint `json:"commentIds" binding:"required,min=1,max=100"`
+	Status     string `json:"status" binding:"required"`
+}
+
+func parseModerationMode(value string) (models.ModerationMode, bool) {
+	switch mode := models.ModerationMode(value); mode {
+	case models.ModerationOpen, models.ModerationFirstTime, models.ModerationAll:
+		return mode, true
+	}
+	return "", false
+}
+
+// parseCommentModeration parses the comment moderation mode of a post. An
+// empty value clears it so the post follows the global mode.
+func parseCommentModeration(value string) (*models.ModerationMode, bool) {
+	if value == "" {
+		return nil, true
+	}
+	mode, ok := parseModerationMode(value)
+	if !ok {
+		return nil, false
+	}
+	return &mode, true
+}
+
+// moderationMode returns the moderation mode that applies to new comments
+// on post: its own mode, or else the global one.
+func moderationMode(post models.Post) models.ModerationMode {
+	if post.CommentModeration != nil {
+		return *post.CommentModeration
+	}
+	if mode, ok := parseModerationMode(config.AppConfig.CommentModeration); ok {
+		return mode
+	}
+	return models.ModerationOpen
+}
+
+// screenComment decides whether a new or edited comment by the current user
+// goes live, waits for a moderator or is refused as spam. Admins and the
+// post's authors are trusted; everyone else's comments go through the spam
+// pipeline and then the post's moderation mode.
+func (h *CommentHandler) screenComment(c *gin.Context, post models.Post, content string) (models.CommentStatus, spam.Result, error) {
+	userID, _ := c.Get("userID")
+	userRole, _ := c.Get("userRole")
+	if userRole.(string) == "admin" {
+		return models.CommentApproved, spam.Result{}, nil
+	}
+
+	role, err := postAuthorRole(post, userID.(uint))
+	if err != nil {
+		return "", spam.Result{}, err
+	}
+	if role != "" {
+		return models.CommentApproved, spam.Result{}, nil
+	}
+
+	var user models.User
+	if err := database.DB.Select("id", "created_at").First(&user, userID).Error; err != nil {
+		return "", spam.Result{}, err
+	}
+	now := time.Now()
+	result, err := h.spam.Evaluate(c.Request.Context(), spam.Comment{
+		UserID:      user.ID,
+		PostID:      post.ID,
+		Content:     content,
+		AccountAge:  now.Sub(user.CreatedAt),
+		SubmittedAt: now,
+	})
+	if err != nil {
+		return "", spam.Result{}, err
+	}
+
+	switch result.Verdict {
+	case spam.VerdictReject:
+		return models.CommentSpam, result, nil
+	case spam.VerdictHold:
+		return models.CommentPending, result, nil
+	}
+
+	switch moderationMode(post) {
+	case models.ModerationAll:
+		return models.CommentPending, result, nil
+	case models.ModerationFirstTime:
+		var approved int64
+		if err := database.DB.Model(&models.Comment{}).
+			Where("user_id = ? AND status = ?", userID, models.CommentApproved).
+			Limit(1).
+			Count(&approved).Error; err != nil {
+			return "", spam.Result{}, err
+		}
+		if approved == 0 {
+			return models.CommentPending, result, nil
+		}
+	}
+	return models.CommentApproved, result, nil
+}
+
+// editedCommentStatus returns the status of a comment in status current
+// after an edit that screenComment gave the status screened. An edit can
+// send an approved comment back to moderation or a comment to spam, but
+// never overturns a moderator's decision in the comment's favour.
+func editedCommentStatus(current, screened models.CommentStatus) models.CommentStatus {
+	switch {
+	case screened == models.CommentSpam && current != models.CommentRejected:
+		return models.CommentSpam
+	case screened == models.CommentPending && current == models.CommentApproved:
+		return models.CommentPending
+	}
+	return current
+}
+
+// trainingClass returns the class a moderation decision teaches the spam
+// classifier, if any. Rejected comments are off-topic or rude rather than
+// spam, so they teach it nothing.
+func trainingClass(status models.CommentStatus) string {
+	switch status {
+	case models.CommentSpam:
+		return spam.ClassSpam
+	case models.CommentApproved:
+		return spam.ClassHam
+	}
+	return ""
+}
+
+// notifyPendingComment tells the authors of a post that comment is waiting
+// for them to moderate it.
+func notifyPendingComment(tx *gorm.DB, post models.Post, comment models.Comment) error {
+	var authorIDs []uint
+	if err := tx.Model(&models.PostAuthor{}).
+		Where("post_id = ? AND accepted_at IS NOT NULL AND user_id <> ?", post.ID, comment.UserID).
+		Pluck("user_id", &authorIDs).Error; err != nil {
+		return err
+	}
+	if len(authorIDs) == 0 {
+		return nil
+	}
+
+	notifications := make([]models.Notification, len(authorIDs))
+	for i, authorID := range authorIDs {
+		notifications[i] = models.Notification{
+			UserID:    authorID,
+			Type:      models.NotificationCommentPending,
+			ActorID:   &comment.UserID,
+			PostID:    &post.ID,
+			CommentID: &comment.ID,
+		}
+	}
+	return tx.Create(&notifications).Error
+}
+
+// moderationScope limits a comment query to the comments the current user
+// may moderate: all of them for admins, otherwise those on their posts.
+func moderationScope(c *gin.Context, query *gorm.DB) *gorm.DB {
+	userID, _ := c.Get("userID")
+	userRole, _ := c.Get("userRole")
+	if userRole.(string) == "admin" {
+		return query
+	}
+	return query.Where(moderatedPostsSQL, userID.(uint))
+}
+
+// GetModerationQueue lists the comments awaiting moderation on the current
+// user's posts, or on every post for admins, oldest first. The status and
+// postId parameters narrow the queue.
+func (h *CommentHandler) GetModerationQueue(c *gin.Context) {
+	page, pageSize := utils.ParsePagination(c, 1, 20, 100)
+
+	status := models.CommentStatus(c.DefaultQuery("status", string(models.CommentPending)))
+	switch status {
+	case models.CommentPending, models.CommentApproved, models.CommentRejected, models.CommentSpam:
+	default:
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("status must be one of pending, approved, rejected, spam"))
+		return
+	}
+
+	query := moderationScope(c, database.DB.Model(&models.Comment{}).Where("comments.status = ?", status))
+	if value := c.Query("postId"); value != "" {
+		postID, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid post ID"))
+			return
+		}
+		query = query.Where("comments.post_id = ?", postID)
+	}
+
+	var comments []models.Comment
+	var total int64
+	query.Count(&total)
+
+	offset := (page - 1) * pageSize
+	if err := query.Preload("User").Preload("Post").
+		Order("comments.created_at ASC, comments.id ASC").
+		Limit(pageSize).
+		Offset(offset).
+		Find(&comments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get moderation queue"))
+		return
+	}
+	for i := range comments {
+		comments[i].Spam = &models.CommentSpamReport{
+			Score:   comments[i].SpamScore,
+			Reasons: comments[i].SpamReasons,
+		}
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(CommentListResponse{
+		Comments:   comments,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: utils.CalculateTotalPages(total, pageSize),
+	}))
+}
+
+// ModerateComments approves, rejects or marks as spam a batch of comments,
+// training the spam classifier on approved and spam ones. Either every
+// comment is updated or, if any is missing or outside what the user may
+// moderate, none is.
+func (h *CommentHandler) ModerateComments(c *gin.Context) {
+	var req ModerateCommentsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request data: "+err.Error()))
+		return
+	}
+
+	status := models.CommentStatus(req.Status)
+	switch status {
+	case models.CommentApproved, models.CommentRejected, models.CommentSpam:
+	default:
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("status must be one of approved, rejected, spam"))
+		return
+	}
+
+	ids := make(map[uint]bool, len(req.CommentIDs))
+	for _, id := range req.CommentIDs {
+		ids[id] = true
+	}
+
+	var found int64
+	if err := database.DB.Model(&models.Comment{}).Where("id IN ?", req.CommentIDs).Count(&found).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to moderate comments"))
+		return
+	}
+	if int(found) != len(ids) {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("comment not found"))
+		return
+	}
+
+	var allowed int64
+	if err := moderationScope(c, database.DB.Model(&models.Comment{})).
+		Where("comments.id IN ?", req.CommentIDs).
+		Count(&allowed).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to moderate comments"))
+		return
+	}
+	if allowed != found {
+		c.JSON(http.StatusForbidden, utils.ErrorResponse("permission denied"))
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	now := time.Now()
+
+	// The classifier is shared by every post, so only admins' decisions
+	// train it; post authors moderate by their own standards.
+	class := ""
+	if userRole, _ := c.Get("userRole"); userRole.(string) == "admin" {
+		class = trainingClass(status)
+	}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var comments []models.Comment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "content", "spam_trained").
+			Where("id IN ?", req.CommentIDs).
+			Find(&comments).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.Comment{}).
+			Where("id IN ?", req.CommentIDs).
+			Updates(map[string]interface{}{
+				"status":       status,
+				"moderated_by": userID.(uint),
+				"moderated_at": now,
+			}).Error; err != nil {
+			return err
+		}
+
+		// Teach the classifier the decision, first unlearning any earlier
+		// decision that it contradicts.
+		if class == "" {
+			return nil
+		}
+		for _, comment := range comments {
+			if comment.SpamTrained != nil && *comment.SpamTrained == class {
+				continue
+			}
+			if comment.SpamTrained != nil {
+				if err := spam.Learn(tx, comment.Content, *comment.SpamTrained, -1); err != nil {
+					return err
+				}
+			}
+			if err := spam.Learn(tx, comment.Content, class, 1); err != nil {
+				return err
+			}
+			if err := tx.Model(&comment).Update("spam_trained", class).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to moderate comments"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(gin.H{"updated": found}))
+}
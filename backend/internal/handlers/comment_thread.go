@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"travel-blog-backend/internal/config"
+	"travel-blog-backend/internal/database"
+	"travel-blog-backend/internal/models"
+	"travel-blog-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Comment sort modes. Top puts the comments with the most replies below
+// them first.
+const (
+	commentSortOldest = "oldest"
+	commentSortNewest = "newest"
+	commentSortTop    = "top"
+)
+
+// commentOrders maps the comment sort modes to their ORDER BY clauses.
+var commentOrders = map[string]string{
+	commentSortOldest: "comments.created_at ASC, comments.id ASC",
+	commentSortNewest: "comments.created_at DESC, comments.id DESC",
+	commentSortTop: `(
+		SELECT COUNT(*) FROM comments descendants
+		WHERE descendants.path LIKE comments.path || '_%' AND descendants.deleted_at IS NULL AND descendants.status = 'approved'
+	) DESC, comments.created_at DESC, comments.id DESC`,
+}
+
+// parseCommentSort reads the sort query parameter, falling back to
+// defaultSort when it is absent. It writes a 400 response on invalid input.
+func parseCommentSort(c *gin.Context, defaultSort string) (string, bool) {
+	sort := strings.ToLower(c.DefaultQuery("sort", defaultSort))
+	if _, ok := commentOrders[sort]; !ok {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("sort must be one of oldest, newest, top"))
+		return "", false
+	}
+	return sort, true
+}
+
+// commentPath returns the materialized path of a comment with the given ID
+// under parent, or at the root of a thread when parent is nil.
+func commentPath(parent *models.Comment, id uint) string {
+	if parent == nil {
+		return fmt.Sprintf("/%d/", id)
+	}
+	return fmt.Sprintf("%s%d/", parent.Path, id)
+}
+
+// commentBranch is a comment found below the parents passed to
+// loadCommentThreads, with its rank among its siblings and their number.
+type commentBranch struct {
+	ID         uint
+	ParentID   uint
+	BranchRank int
+	BranchSize int
+}
+
+// loadCommentThreads fills in the replies below each of parents, sorted by
+// sortMode. Every branch holds at most CommentRepliesPerBranch replies and
+// threads go at most CommentThreadDepth levels down; the rest are fetched
+// per branch through GetReplies. The cut is made in SQL, so each call reads
+// a bounded number of rows however large the threads are.
+func loadCommentThreads(c *gin.Context, parents []models.Comment, sortMode string) error {
+	if len(parents) == 0 {
+		return nil
+	}
+
+	limit := config.AppConfig.CommentRepliesPerBranch
+	depth := config.AppConfig.CommentThreadDepth
+
+	// One level more than is shown is read so that the deepest comments
+	// still learn how many replies they have.
+	subtrees := database.DB
+	for _, parent := range parents {
+		subtrees = subtrees.Or("comments.path LIKE ? AND comments.depth <= ?", parent.Path+"_%", parent.Depth+depth+1)
+	}
+	ranked := visibleComments(c, database.DB.Unscoped().Model(&models.Comment{}).
+		Select("comments.id, comments.parent_id, "+
+			"ROW_NUMBER() OVER (PARTITION BY comments.parent_id ORDER BY "+commentOrders[sortMode]+") AS branch_rank, "+
+			"COUNT(*) OVER (PARTITION BY comments.parent_id) AS branch_size").
+		Where(subtrees))
+
+	var branches []commentBranch
+	if err := database.DB.Table("(?) AS branches", ranked).
+		Where("branch_rank <= ?", limit).
+		Order("parent_id, branch_rank").
+		Find(&branches).Error; err != nil {
+		return err
+	}
+
+	children := make(map[uint][]commentBranch)
+	for _, branch := range branches {
+		children[branch.ParentID] = append(children[branch.ParentID], branch)
+	}
+
+	// Walk down from parents to find the replies that are shown. Comments
+	// whose own parent fell outside its branch's limit are skipped.
+	var shown []uint
+	var collect func(id uint, level int)
+	collect = func(id uint, level int) {
+		if level > depth {
+			return
+		}
+		for _, child := range children[id] {
+			shown = append(shown, child.ID)
+			collect(child.ID, level+1)
+		}
+	}
+	for _, parent := range parents {
+		collect(parent.ID, 1)
+	}
+
+	var replies []models.Comment
+	if len(shown) > 0 {
+		if err := database.DB.Unscoped().Preload("User").Where("id IN ?", shown).Find(&replies).Error; err != nil {
+			return err
+		}
+	}
+	byID := make(map[uint]models.Comment, len(replies))
+	for _, reply := range replies {
+		byID[reply.ID] = reply
+	}
+
+	var attach func(parent *models.Comment, level int)
+	attach = func(parent *models.Comment, level int) {
+		siblings := children[parent.ID]
+		if len(siblings) > 0 {
+			parent.ReplyCount = siblings[0].BranchSize
+		}
+		if level > depth {
+			return
+		}
+		parent.Replies = make([]models.Comment, 0, len(siblings))
+		for _, sibling := range siblings {
+			reply, ok := byID[sibling.ID]
+			if !ok {
+				continue
+			}
+			attach(&reply, level+1)
+			parent.Replies = append(parent.Replies, reply)
+		}
+	}
+	for i := range parents {
+		attach(&parents[i], 1)
+	}
+	return nil
+}
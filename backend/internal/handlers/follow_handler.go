@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"travel-blog-backend/internal/database"
+	"travel-blog-backend/internal/models"
+	"travel-blog-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FollowHandler struct{}
+
+func NewFollowHandler() *FollowHandler {
+	return &FollowHandler{}
+}
+
+type FollowListResponse struct {
+	Users      []models.User `json:"users"`
+	Total      int64         `json:"total"`
+	Page       int           `json:"page"`
+	PageSize   int           `json:"pageSize"`
+	TotalPages int           `json:"totalPages"`
+}
+
+func (h *FollowHandler) findUser(c *gin.Context, user *models.User) bool {
+	id, ok := utils.ParseUintParam(c, "id", "Invalid user ID")
+	if !ok {
+		return false
+	}
+
+	if err := database.DB.Select("id").First(user, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("user not found"))
+		} else {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get user"))
+		}
+		return false
+	}
+	return true
+}
+
+// Follow makes the current user follow another user. Following someone
+// twice is a no-op.
+func (h *FollowHandler) Follow(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var user models.User
+	if !h.findUser(c, &user) {
+		return
+	}
+	if user.ID == userID.(uint) {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("you cannot follow yourself"))
+		return
+	}
+
+	follow := models.Follow{FollowerID: userID.(uint), FollowingID: user.ID}
+	if err := database.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&follow).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to follow user"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.MessageResponse("User followed"))
+}
+
+// Unfollow stops the current user following another user. Unfollowing
+// someone not followed is a no-op.
+func (h *FollowHandler) Unfollow(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var user models.User
+	if !h.findUser(c, &user) {
+		return
+	}
+
+	if err := database.DB.
+		Where("follower_id = ? AND following_id = ?", userID.(uint), user.ID).
+		Delete(&models.Follow{}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to unfollow user"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.MessageResponse("User unfollowed"))
+}
+
+// GetFollowers lists the users following the :id user, most recent first.
+func (h *FollowHandler) GetFollowers(c *gin.Context) {
+	h.list(c, "following_id", "follower_id")
+}
+
+// GetFollowing lists the users the :id user follows, most recent first.
+func (h *FollowHandler) GetFollowing(c *gin.Context) {
+	h.list(c, "follower_id", "following_id")
+}
+
+// list returns the users in the other column of the follows of the :id user
+// in the match column.
+func (h *FollowHandler) list(c *gin.Context, match, other string) {
+	var user models.User
+	if !h.findUser(c, &user) {
+		return
+	}
+
+	page, pageSize := utils.ParsePagination(c, 1, 20, 100)
+
+	var users []models.User
+	var total int64
+
+	query := database.DB.Model(&models.User{}).
+		Joins("JOIN follows ON follows."+other+" = users.id").
+		Where("follows."+match+" = ?", user.ID)
+	query.Count(&total)
+
+	offset := (page - 1) * pageSize
+	if err := query.
+		Order("follows.created_at DESC, users.id DESC").
+		Limit(pageSize).
+		Offset(offset).
+		Find(&users).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get users"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(FollowListResponse{
+		Users:      users,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: utils.CalculateTotalPages(total, pageSize),
+	}))
+}
@@ -0,0 +1,297 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+	"travel-blog-backend/internal/database"
+	"travel-blog-backend/internal/models"
+	"travel-blog-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type GalleryHandler struct{}
+
+func NewGalleryHandler() *GalleryHandler {
+	return &GalleryHandler{}
+}
+
+type AddGalleryImageRequest struct {
+	MediaID uint       `json:"mediaId" binding:"required"`
+	Caption *string    `json:"caption"`
+	AltText *string    `json:"altText"`
+	TakenAt *time.Time `json:"takenAt"`
+}
+
+type UpdateGalleryImageRequest struct {
+	Caption *string    `json:"caption"`
+	AltText *string    `json:"altText"`
+	TakenAt *time.Time `json:"takenAt"`
+}
+
+type ReorderGalleryRequest struct {
+	ImageIDs []uint `json:"imageIds" binding:"required"`
+}
+
+type GalleryListResponse struct {
+	Images     []models.GalleryImage `json:"images"`
+	Total      int64                 `json:"total"`
+	Page       int                   `json:"page"`
+	PageSize   int                   `json:"pageSize"`
+	TotalPages int                   `json:"totalPages"`
+}
+
+// imageOwner loads a gallery image and checks that the current user may
+// edit the post or trip it belongs to.
+func (h *GalleryHandler) imageOwner(c *gin.Context, image *models.GalleryImage) (attachmentOwner, bool) {
+	id, ok := utils.ParseUintParam(c, "id", "Invalid image ID")
+	if !ok {
+		return attachmentOwner{}, false
+	}
+
+	if err := database.DB.First(image, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("image not found"))
+		} else {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get image"))
+		}
+		return attachmentOwner{}, false
+	}
+
+	return attachmentOwnerOf(c, image.PostID, image.TripID)
+}
+
+func (h *GalleryHandler) GetPostGallery(c *gin.Context) {
+	if owner, ok := postAttachmentOwner(c, false); ok {
+		h.list(c, owner)
+	}
+}
+
+func (h *GalleryHandler) AddPostImage(c *gin.Context) {
+	if owner, ok := postAttachmentOwner(c, true); ok {
+		h.add(c, owner)
+	}
+}
+
+func (h *GalleryHandler) ReorderPostGallery(c *gin.Context) {
+	if owner, ok := postAttachmentOwner(c, true); ok {
+		h.reorder(c, owner)
+	}
+}
+
+func (h *GalleryHandler) GetTripGallery(c *gin.Context) {
+	if owner, ok := tripAttachmentOwner(c, false); ok {
+		h.list(c, owner)
+	}
+}
+
+func (h *GalleryHandler) AddTripImage(c *gin.Context) {
+	if owner, ok := tripAttachmentOwner(c, true); ok {
+		h.add(c, owner)
+	}
+}
+
+func (h *GalleryHandler) ReorderTripGallery(c *gin.Context) {
+	if owner, ok := tripAttachmentOwner(c, true); ok {
+		h.reorder(c, owner)
+	}
+}
+
+func (h *GalleryHandler) list(c *gin.Context, owner attachmentOwner) {
+	page, pageSize := utils.ParsePagination(c, 1, 24, 100)
+
+	var images []models.GalleryImage
+	var total int64
+
+	query := owner.scope(database.DB.Model(&models.GalleryImage{}))
+	query.Count(&total)
+
+	offset := (page - 1) * pageSize
+	if err := query.Preload("Media").
+		Order("position ASC, id ASC").
+		Limit(pageSize).
+		Offset(offset).
+		Find(&images).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get gallery"))
+		return
+	}
+	for i := range images {
+		hideMediaLocation(c, &images[i].Media)
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(GalleryListResponse{
+		Images:     images,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: utils.CalculateTotalPages(total, pageSize),
+	}))
+}
+
+func (h *GalleryHandler) add(c *gin.Context, owner attachmentOwner) {
+	var req AddGalleryImageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request data: "+err.Error()))
+		return
+	}
+
+	var item models.Media
+	if err := database.DB.First(&item, req.MediaID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("media not found"))
+		} else {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get media"))
+		}
+		return
+	}
+	if !checkMediaPermission(c, item) {
+		return
+	}
+
+	image := models.GalleryImage{
+		MediaID: item.ID,
+		Caption: req.Caption,
+		AltText: req.AltText,
+		TakenAt: item.TakenAt,
+	}
+	if req.TakenAt != nil {
+		image.TakenAt = req.TakenAt
+	}
+	image.PostID, image.TripID = owner.ids()
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := owner.lock(tx); err != nil {
+			return err
+		}
+		var last int
+		if err := owner.scope(tx.Model(&models.GalleryImage{})).
+			Select("COALESCE(MAX(position), 0)").
+			Scan(&last).Error; err != nil {
+			return err
+		}
+		image.Position = last + 1
+		return tx.Create(&image).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to add image"))
+		return
+	}
+
+	image.Media = item
+	hideMediaLocation(c, &image.Media)
+	c.JSON(http.StatusCreated, utils.SuccessResponse(image))
+}
+
+func (h *GalleryHandler) reorder(c *gin.Context, owner attachmentOwner) {
+	var req ReorderGalleryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request data: "+err.Error()))
+		return
+	}
+
+	errMismatch := errors.New("imageIds must list every image in the gallery exactly once")
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := owner.lock(tx); err != nil {
+			return err
+		}
+
+		var ids []uint
+		if err := owner.scope(tx.Model(&models.GalleryImage{})).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		existing := make(map[uint]bool, len(ids))
+		for _, id := range ids {
+			existing[id] = true
+		}
+		if len(req.ImageIDs) != len(ids) {
+			return errMismatch
+		}
+		for _, id := range req.ImageIDs {
+			if !existing[id] {
+				return errMismatch
+			}
+			delete(existing, id)
+		}
+
+		for i, id := range req.ImageIDs {
+			if err := tx.Model(&models.GalleryImage{}).
+				Where("id = ?", id).
+				Update("position", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, errMismatch) {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to reorder gallery"))
+		}
+		return
+	}
+
+	h.list(c, owner)
+}
+
+func (h *GalleryHandler) UpdateImage(c *gin.Context) {
+	var req UpdateGalleryImageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request data: "+err.Error()))
+		return
+	}
+
+	var image models.GalleryImage
+	if _, ok := h.imageOwner(c, &image); !ok {
+		return
+	}
+
+	updates := make(map[string]interface{})
+	if req.Caption != nil {
+		updates["caption"] = req.Caption
+	}
+	if req.AltText != nil {
+		updates["alt_text"] = req.AltText
+	}
+	if req.TakenAt != nil {
+		updates["taken_at"] = req.TakenAt
+	}
+
+	if err := database.DB.Model(&image).Updates(updates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to update image"))
+		return
+	}
+
+	database.DB.Preload("Media").First(&image, image.ID)
+	hideMediaLocation(c, &image.Media)
+	c.JSON(http.StatusOK, utils.SuccessResponse(image))
+}
+
+func (h *GalleryHandler) DeleteImage(c *gin.Context) {
+	var image models.GalleryImage
+	owner, ok := h.imageOwner(c, &image)
+	if !ok {
+		return
+	}
+
+	// Close the gap left behind so positions stay contiguous.
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := owner.lock(tx); err != nil {
+			return err
+		}
+		if err := tx.Delete(&image).Error; err != nil {
+			return err
+		}
+		return owner.scope(tx.Model(&models.GalleryImage{})).
+			Where("position > ?", image.Position).
+			Update("position", gorm.Expr("position - 1")).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to delete image"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.MessageResponse("Image removed from gallery"))
+}
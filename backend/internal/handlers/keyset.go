@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"travel-blog-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// keysetRequest holds the parameters of a cursor-paginated listing. Clients
+// opt in by sending a cursor parameter, empty for the first page.
+type keysetRequest struct {
+	cursor *utils.Cursor
+	desc   bool
+	limit  int
+}
+
+// parseKeysetRequest reports whether the request uses cursor pagination and
+// parses its cursor and order parameters. It writes a 400 response and
+// returns ok=false on invalid input.
+func parseKeysetRequest(c *gin.Context, limit int) (req keysetRequest, enabled, ok bool) {
+	raw, enabled := c.GetQuery("cursor")
+	if !enabled {
+		return req, false, true
+	}
+	req.limit = limit
+
+	if raw != "" {
+		cursor, err := utils.DecodeCursor(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+			return req, true, false
+		}
+		req.cursor = &cursor
+	}
+
+	switch strings.ToLower(c.DefaultQuery("order", "desc")) {
+	case "desc":
+		req.desc = true
+	case "asc":
+	default:
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("order must be asc or desc"))
+		return req, true, false
+	}
+	return req, true, true
+}
+
+// keysetPage loads one page of query ordered by created_at and id, starting
+// after (or, for backward cursors, before) the request's cursor. key returns
+// the position of an item. Cursors are only returned when there is a page in
+// that direction.
+func keysetPage[T any](query *gorm.DB, table string, req keysetRequest, dest *[]T, key func(T) (time.Time, uint)) (next, prev *string, err error) {
+	backward := req.cursor != nil && req.cursor.Backward
+
+	// Walking backward scans in the opposite order and flips the page after.
+	scanDesc := req.desc != backward
+	direction, comparison := "ASC", ">"
+	if scanDesc {
+		direction, comparison = "DESC", "<"
+	}
+
+	if req.cursor != nil {
+		query = query.Where(
+			fmt.Sprintf("(%s.created_at, %s.id) %s (?, ?)", table, table, comparison),
+			req.cursor.CreatedAt, req.cursor.ID,
+		)
+	}
+	if err := query.
+		Order(fmt.Sprintf("%s.created_at %s, %s.id %s", table, direction, table, direction)).
+		Limit(req.limit + 1).
+		Find(dest).Error; err != nil {
+		return nil, nil, err
+	}
+
+	items := *dest
+	more := len(items) > req.limit
+	if more {
+		items = items[:req.limit]
+	}
+	if backward {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+	*dest = items
+
+	if len(items) == 0 {
+		return nil, nil, nil
+	}
+
+	hasNext, hasPrev := more, req.cursor != nil
+	if backward {
+		hasNext, hasPrev = true, more
+	}
+	if hasNext {
+		createdAt, id := key(items[len(items)-1])
+		cursor := utils.EncodeCursor(utils.Cursor{CreatedAt: createdAt, ID: id})
+		next = &cursor
+	}
+	if hasPrev {
+		createdAt, id := key(items[0])
+		cursor := utils.EncodeCursor(utils.Cursor{CreatedAt: createdAt, ID: id, Backward: true})
+		prev = &cursor
+	}
+	return next, prev, nil
+}
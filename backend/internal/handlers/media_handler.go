@@ -0,0 +1,344 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+	"travel-blog-backend/internal/config"
+	"travel-blog-backend/internal/database"
+	"travel-blog-backend/internal/media"
+	"travel-blog-backend/internal/models"
+	"travel-blog-backend/internal/storage"
+	"travel-blog-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// suggestPlaceRadiusKm bounds how far a photo may have been taken from a
+// place for that place to be suggested as the post location.
+const suggestPlaceRadiusKm = 50
+
+type MediaHandler struct {
+	storage storage.Storage
+}
+
+func NewMediaHandler(store storage.Storage) *MediaHandler {
+	return &MediaHandler{storage: store}
+}
+
+type UploadMediaResponse struct {
+	Media          models.Media  `json:"media"`
+	SuggestedPlace *models.Place `json:"suggestedPlace,omitempty"`
+}
+
+type MediaListResponse struct {
+	Media      []models.Media `json:"media"`
+	Total      int64          `json:"total"`
+	Page       int            `json:"page"`
+	PageSize   int            `json:"pageSize"`
+	TotalPages int            `json:"totalPages"`
+}
+
+func (h *MediaHandler) Upload(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("User not authenticated"))
+		return
+	}
+
+	maxBytes := int64(config.AppConfig.MaxUploadMB) << 20
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes+1<<20)
+
+	file, _, err := c.Request.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, utils.ErrorResponse(fmt.Sprintf("file must not exceed %d MB", config.AppConfig.MaxUploadMB)))
+			return
+		}
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("file is required"))
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxBytes+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("failed to read file"))
+		return
+	}
+	if int64(len(data)) > maxBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, utils.ErrorResponse(fmt.Sprintf("file must not exceed %d MB", config.AppConfig.MaxUploadMB)))
+		return
+	}
+
+	processed, err := media.Process(data)
+	if err != nil {
+		switch {
+		case errors.Is(err, media.ErrUnsupportedType):
+			c.JSON(http.StatusUnsupportedMediaType, utils.ErrorResponse("only JPEG, PNG, GIF and WebP images are supported"))
+		case errors.Is(err, media.ErrImageTooLarge):
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("image dimensions are too large"))
+		default:
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to process image"))
+		}
+		return
+	}
+
+	base, err := newMediaKey()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to store image"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	stored := make([]string, 0, len(processed.Thumbnails)+1)
+	cleanup := func() {
+		for _, key := range stored {
+			if err := h.storage.Delete(ctx, key); err != nil {
+				log.Printf("Failed to delete %s: %v", key, err)
+			}
+		}
+	}
+
+	key := base + processed.Extension
+	if err := h.storage.Put(ctx, key, processed.Data, processed.ContentType); err != nil {
+		log.Printf("Failed to store %s: %v", key, err)
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to store image"))
+		return
+	}
+	stored = append(stored, key)
+
+	thumbExt, thumbType := processed.ThumbnailExtension()
+	variants := make(models.MediaVariants, 0, len(processed.Thumbnails))
+	for _, thumb := range processed.Thumbnails {
+		thumbKey := fmt.Sprintf("%s_%d%s", base, thumb.Width, thumbExt)
+		if err := h.storage.Put(ctx, thumbKey, thumb.Data, thumbType); err != nil {
+			log.Printf("Failed to store %s: %v", thumbKey, err)
+			cleanup()
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to store image"))
+			return
+		}
+		stored = append(stored, thumbKey)
+		variants = append(variants, models.MediaVariant{
+			Width:  thumb.Width,
+			Height: thumb.Height,
+			Key:    thumbKey,
+			URL:    h.storage.URL(thumbKey),
+		})
+	}
+
+	item := models.Media{
+		UserID:      userID.(uint),
+		Key:         key,
+		URL:         h.storage.URL(key),
+		ContentType: processed.ContentType,
+		Size:        int64(len(processed.Data)),
+		Width:       processed.Width,
+		Height:      processed.Height,
+		Variants:    variants,
+		TakenAt:     processed.Metadata.TakenAt,
+	}
+
+	// GPS coordinates are only kept when the uploader explicitly asks for
+	// them; they never end up in the stored files either way.
+	keepLocation := c.PostForm("keepLocation") == "true"
+	if keepLocation && processed.Metadata.Latitude != nil {
+		item.Latitude = processed.Metadata.Latitude
+		item.Longitude = processed.Metadata.Longitude
+	}
+
+	if err := database.DB.Create(&item).Error; err != nil {
+		cleanup()
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to save media"))
+		return
+	}
+
+	response := UploadMediaResponse{Media: item}
+	if item.Latitude != nil {
+		place, err := nearestPlace(*item.Latitude, *item.Longitude, suggestPlaceRadiusKm)
+		if err != nil {
+			log.Printf("Failed to suggest place for media %d: %v", item.ID, err)
+		}
+		response.SuggestedPlace = place
+	}
+
+	c.JSON(http.StatusCreated, utils.SuccessResponse(response))
+}
+
+func (h *MediaHandler) GetMyMedia(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("User not authenticated"))
+		return
+	}
+
+	page, pageSize := utils.ParsePagination(c, 1, 20, 100)
+
+	var items []models.Media
+	var total int64
+
+	query := database.DB.Model(&models.Media{}).Where("user_id = ?", userID.(uint))
+	query.Count(&total)
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("created_at DESC").
+		Limit(pageSize).
+		Offset(offset).
+		Find(&items).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get media"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(MediaListResponse{
+		Media:      items,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: utils.CalculateTotalPages(total, pageSize),
+	}))
+}
+
+func (h *MediaHandler) GetMedia(c *gin.Context) {
+	id, ok := utils.ParseUintParam(c, "id", "Invalid media ID")
+	if !ok {
+		return
+	}
+
+	var item models.Media
+	if !findMedia(c, id, &item) {
+		return
+	}
+
+	visible, err := canViewMedia(c, item)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get media"))
+		return
+	}
+	if !visible {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("media not found"))
+		return
+	}
+
+	hideMediaLocation(c, &item)
+	c.JSON(http.StatusOK, utils.SuccessResponse(item))
+}
+
+func (h *MediaHandler) DeleteMedia(c *gin.Context) {
+	id, ok := utils.ParseUintParam(c, "id", "Invalid media ID")
+	if !ok {
+		return
+	}
+
+	var item models.Media
+	if !findMedia(c, id, &item) {
+		return
+	}
+
+	if !checkMediaPermission(c, item) {
+		return
+	}
+
+	if err := database.DB.Delete(&item).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to delete media"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	keys := []string{item.Key}
+	for _, variant := range item.Variants {
+		keys = append(keys, variant.Key)
+	}
+	for _, key := range keys {
+		if err := h.storage.Delete(ctx, key); err != nil {
+			log.Printf("Failed to delete %s: %v", key, err)
+		}
+	}
+
+	c.JSON(http.StatusOK, utils.MessageResponse("Media deleted successfully"))
+}
+
+func findMedia(c *gin.Context, id uint, item *models.Media) bool {
+	if err := database.DB.First(item, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("media not found"))
+		} else {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get media"))
+		}
+		return false
+	}
+	return true
+}
+
+func checkMediaPermission(c *gin.Context, item models.Media) bool {
+	userID, _ := c.Get("userID")
+	userRole, _ := c.Get("userRole")
+	if item.UserID != userID.(uint) && userRole.(string) != "admin" {
+		c.JSON(http.StatusForbidden, utils.ErrorResponse("permission denied"))
+		return false
+	}
+	return true
+}
+
+// canViewMedia reports whether the current reader may open item. Its
+// uploader and admins always can; everyone else only once it is in the
+// gallery of a trip or of a post they can see.
+func canViewMedia(c *gin.Context, item models.Media) (bool, error) {
+	if userID, signedIn := c.Get("userID"); signedIn {
+		userRole, _ := c.Get("userRole")
+		if item.UserID == userID.(uint) || userRole.(string) == "admin" {
+			return true, nil
+		}
+	}
+
+	var images []models.GalleryImage
+	if err := database.DB.Select("post_id", "trip_id").Where("media_id = ?", item.ID).Find(&images).Error; err != nil {
+		return false, err
+	}
+	postIDs := make([]uint, 0, len(images))
+	for _, image := range images {
+		if image.TripID != nil {
+			return true, nil
+		}
+		postIDs = append(postIDs, *image.PostID)
+	}
+	if len(postIDs) == 0 {
+		return false, nil
+	}
+
+	var posts []models.Post
+	if err := database.DB.Select(postVisibilityColumns).Where("id IN ?", postIDs).Find(&posts).Error; err != nil {
+		return false, err
+	}
+	for _, post := range posts {
+		if visible, err := canViewPost(c, post); err != nil || visible {
+			return visible, err
+		}
+	}
+	return false, nil
+}
+
+// hideMediaLocation clears the GPS coordinates of item unless the current
+// user uploaded it. Coordinates can pinpoint someone's home, so they are
+// never shown to other readers, admins included.
+func hideMediaLocation(c *gin.Context, item *models.Media) {
+	if userID, signedIn := c.Get("userID"); signedIn && item.UserID == userID.(uint) {
+		return
+	}
+	item.Latitude = nil
+	item.Longitude = nil
+}
+
+// newMediaKey returns a unique, unguessable key prefix grouped by month.
+func newMediaKey() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return time.Now().UTC().Format("media/2006/01/") + hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+	"travel-blog-backend/internal/database"
+	"travel-blog-backend/internal/models"
+	"travel-blog-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type NotificationHandler struct{}
+
+func NewNotificationHandler() *NotificationHandler {
+	return &NotificationHandler{}
+}
+
+type NotificationListResponse struct {
+	Notifications []models.Notification `json:"notifications"`
+	Total         int64                 `json:"total"`
+	Unread        int64                 `json:"unread"`
+	Page          int                   `json:"page"`
+	PageSize      int                   `json:"pageSize"`
+	TotalPages    int                   `json:"totalPages"`
+}
+
+// GetNotifications lists the current user's notifications, newest first.
+// Passing unread=true leaves out the ones already read.
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	page, pageSize := utils.ParsePagination(c, 1, 20, 100)
+
+	var unread int64
+	if err := database.DB.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&unread).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get notifications"))
+		return
+	}
+
+	query := database.DB.Model(&models.Notification{}).Where("user_id = ?", userID)
+	if c.Query("unread") == "true" {
+		query = query.Where("read_at IS NULL")
+	}
+
+	var notifications []models.Notification
+	var total int64
+	query.Count(&total)
+
+	offset := (page - 1) * pageSize
+	if err := query.Preload("Actor").Preload("Post").Preload("Comment").
+		Order("created_at DESC, id DESC").
+		Limit(pageSize).
+		Offset(offset).
+		Find(&notifications).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get notifications"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(NotificationListResponse{
+		Notifications: notifications,
+		Total:         total,
+		Unread:        unread,
+		Page:          page,
+		PageSize:      pageSize,
+		TotalPages:    utils.CalculateTotalPages(total, pageSize),
+	}))
+}
+
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	id, ok := utils.ParseUintParam(c, "id", "Invalid notification ID")
+	if !ok {
+		return
+	}
+	userID, _ := c.Get("userID")
+
+	var notification models.Notification
+	if err := database.DB.Where("user_id = ?", userID).First(&notification, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("notification not found"))
+		} else {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get notification"))
+		}
+		return
+	}
+
+	if notification.ReadAt == nil {
+		if err := database.DB.Model(&notification).Update("read_at", time.Now()).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to update notification"))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, utils.MessageResponse("Notification marked as read"))
+}
+
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	result := database.DB.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", time.Now())
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to update notifications"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(gin.H{"updated": result.RowsAffected}))
+}
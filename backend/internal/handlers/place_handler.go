@@ -0,0 +1,270 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"travel-blog-backend/internal/database"
+	"travel-blog-backend/internal/models"
+	"travel-blog-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PlaceHandler struct{}
+
+func NewPlaceHandler() *PlaceHandler {
+	return &PlaceHandler{}
+}
+
+type PlaceDetailResponse struct {
+	models.Place
+	Ancestors []models.Place `json:"ancestors"`
+}
+
+type PlaceSuggestion struct {
+	ID    uint             `json:"id"`
+	Name  string           `json:"name"`
+	Type  models.PlaceType `json:"type"`
+	Slug  string           `json:"slug"`
+	Label string           `json:"label"`
+}
+
+var placeTypes = map[string]bool{
+	string(models.PlaceContinent): true,
+	string(models.PlaceCountry):   true,
+	string(models.PlaceRegion):    true,
+	string(models.PlaceCity):      true,
+	string(models.PlacePOI):       true,
+}
+
+// placeDescendantsSQL selects the ID of a place and of every place below it.
+const placeDescendantsSQL = `WITH RECURSIVE descendants AS (
+	SELECT id FROM places WHERE id = ?
+	UNION ALL
+	SELECT places.id FROM places JOIN descendants ON places.parent_id = descendants.id
+) SELECT id FROM descendants`
+
+// placeAncestors returns the chain of places above id, from the root down.
+func placeAncestors(id uint) ([]models.Place, error) {
+	var ancestors []models.Place
+	err := database.DB.Raw(`WITH RECURSIVE ancestors AS (
+		SELECT places.*, 0 AS depth FROM places WHERE id = ?
+		UNION ALL
+		SELECT places.*, ancestors.depth + 1 FROM places JOIN ancestors ON places.id = ancestors.parent_id
+	) SELECT id, slug, name, type, parent_id, country_code, latitude, longitude
+	FROM ancestors WHERE depth > 0 ORDER BY depth DESC`, id).Scan(&ancestors).Error
+	return ancestors, err
+}
+
+// placeCountryName returns the name of the country containing the place, or
+// of the place itself when it is a country.
+func placeCountryName(id uint) (*string, error) {
+	var place models.Place
+	if err := database.DB.First(&place, id).Error; err != nil {
+		return nil, err
+	}
+	if place.Type == models.PlaceCountry {
+		return &place.Name, nil
+	}
+
+	ancestors, err := placeAncestors(id)
+	if err != nil {
+		return nil, err
+	}
+	for _, ancestor := range ancestors {
+		if ancestor.Type == models.PlaceCountry {
+			name := ancestor.Name
+			return &name, nil
+		}
+	}
+	return nil, nil
+}
+
+func (h *PlaceHandler) GetPlaces(c *gin.Context) {
+	query := database.DB.Model(&models.Place{})
+
+	if raw := c.Query("parentId"); raw != "" {
+		parentID, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid parent ID"))
+			return
+		}
+		query = query.Where("parent_id = ?", parentID)
+	} else if placeType := c.Query("type"); placeType != "" {
+		if !placeTypes[placeType] {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid place type"))
+			return
+		}
+		query = query.Where("type = ?", placeType)
+	} else {
+		query = query.Where("parent_id IS NULL")
+	}
+
+	var places []models.Place
+	if err := query.Order("name ASC").Find(&places).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get places"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(places))
+}
+
+func (h *PlaceHandler) GetPlace(c *gin.Context) {
+	id, ok := utils.ParseUintParam(c, "id", "Invalid place ID")
+	if !ok {
+		return
+	}
+
+	var place models.Place
+	if err := database.DB.Preload("Children", func(db *gorm.DB) *gorm.DB {
+		return db.Order("name ASC")
+	}).First(&place, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("place not found"))
+		} else {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get place"))
+		}
+		return
+	}
+
+	ancestors, err := placeAncestors(place.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get place"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(PlaceDetailResponse{
+		Place:     place,
+		Ancestors: ancestors,
+	}))
+}
+
+func (h *PlaceHandler) Autocomplete(c *gin.Context) {
+	q := strings.TrimSpace(c.Query("q"))
+	if len([]rune(q)) < 2 {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("query must be at least 2 characters"))
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if limit < 1 || limit > 20 {
+		limit = 10
+	}
+
+	pattern := escapeLike(strings.ToLower(q))
+	query := database.DB.Model(&models.Place{}).
+		Where("lower(name) LIKE ? OR lower(name) LIKE ?", pattern+"%", "% "+pattern+"%")
+	if placeType := c.Query("type"); placeType != "" {
+		if !placeTypes[placeType] {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid place type"))
+			return
+		}
+		query = query.Where("type = ?", placeType)
+	}
+
+	var places []models.Place
+	if err := query.
+		Clauses(clause.OrderBy{Expression: clause.Expr{
+			SQL: `lower(name) LIKE ? DESC,
+				CASE type WHEN 'country' THEN 0 WHEN 'city' THEN 1 WHEN 'region' THEN 2 WHEN 'poi' THEN 3 ELSE 4 END,
+				name ASC`,
+			Vars:               []interface{}{pattern + "%"},
+			WithoutParentheses: true,
+		}}).
+		Limit(limit).
+		Find(&places).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to search places"))
+		return
+	}
+
+	labels, err := placeLabels(places)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to search places"))
+		return
+	}
+
+	suggestions := make([]PlaceSuggestion, 0, len(places))
+	for _, place := range places {
+		suggestions = append(suggestions, PlaceSuggestion{
+			ID:    place.ID,
+			Name:  place.Name,
+			Type:  place.Type,
+			Slug:  place.Slug,
+			Label: labels[place.ID],
+		})
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(suggestions))
+}
+
+// placeLabels builds display labels such as "Lisbon, Lisbon District,
+// Portugal" for the given places with a single query. Continents are left
+// out of the label since they add little to disambiguation.
+func placeLabels(places []models.Place) (map[uint]string, error) {
+	labels := make(map[uint]string, len(places))
+	if len(places) == 0 {
+		return labels, nil
+	}
+
+	ids := make([]uint, 0, len(places))
+	for _, place := range places {
+		ids = append(ids, place.ID)
+		labels[place.ID] = place.Name
+	}
+
+	var rows []struct {
+		PlaceID uint
+		Name    string
+	}
+	err := database.DB.Raw(`WITH RECURSIVE chain AS (
+		SELECT id AS place_id, parent_id AS ancestor_id, 1 AS depth FROM places WHERE id IN ? AND parent_id IS NOT NULL
+		UNION ALL
+		SELECT chain.place_id, places.parent_id, chain.depth + 1
+		FROM chain JOIN places ON places.id = chain.ancestor_id
+		WHERE places.parent_id IS NOT NULL
+	) SELECT chain.place_id, places.name FROM chain
+	JOIN places ON places.id = chain.ancestor_id
+	WHERE places.type <> ?
+	ORDER BY chain.place_id, chain.depth`, ids, models.PlaceContinent).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		labels[row.PlaceID] += ", " + row.Name
+	}
+	return labels, nil
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// nearestPlace returns the closest city or point of interest to the given
+// coordinates within maxKm, or nil when nothing is that close.
+func nearestPlace(latitude, longitude, maxKm float64) (*models.Place, error) {
+	var row struct {
+		models.Place
+		Distance float64
+	}
+	err := database.DB.Raw(`SELECT places.*, 6371 * acos(LEAST(1,
+			cos(radians(?)) * cos(radians(latitude)) * cos(radians(longitude) - radians(?)) +
+			sin(radians(?)) * sin(radians(latitude))
+		)) AS distance
+		FROM places
+		WHERE latitude IS NOT NULL AND longitude IS NOT NULL AND type IN ?
+		ORDER BY distance
+		LIMIT 1`, latitude, longitude, latitude, []models.PlaceType{models.PlaceCity, models.PlacePOI}).
+		Scan(&row).Error
+	if err != nil {
+		return nil, err
+	}
+	if row.ID == 0 || row.Distance > maxKm {
+		return nil, nil
+	}
+	return &row.Place, nil
+}
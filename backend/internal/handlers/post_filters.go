@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+	"travel-blog-backend/internal/models"
+	"travel-blog-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// postSortColumns maps the accepted values of the sort query parameter to
+// the SQL they order by. Only these expressions ever reach ORDER BY.
+var postSortColumns = map[string]string{
+	"created":    "posts.created_at",
+	"published":  "posts.published_at",
+	"updated":    "posts.updated_at",
+	"title":      "LOWER(posts.title)",
+	"popularity": "posts.view_count",
+}
+
+// postSortOrder builds the ORDER BY clause from the sort and order query
+// parameters, falling back to defaultOrder when sort is absent.
+func postSortOrder(c *gin.Context, defaultOrder string) (string, error) {
+	sort := c.Query("sort")
+	direction := strings.ToLower(c.DefaultQuery("order", "desc"))
+	if direction != "asc" && direction != "desc" {
+		return "", errors.New("order must be asc or desc")
+	}
+	if sort == "" {
+		if c.Query("order") == "" {
+			return defaultOrder, nil
+		}
+		sort = "published"
+	}
+
+	column, ok := postSortColumns[sort]
+	if !ok {
+		return "", errors.New("sort must be one of created, published, updated, title, popularity")
+	}
+
+	nulls := ""
+	if sort == "published" {
+		nulls = " NULLS LAST"
+	}
+	return column + " " + strings.ToUpper(direction) + nulls + ", posts.id " + strings.ToUpper(direction), nil
+}
+
+// parseDateParam accepts either a date or an RFC 3339 timestamp. A bare date
+// used as an upper bound covers the whole day.
+func parseDateParam(value string, upper bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := utils.ParseDate(value)
+	if err != nil {
+		return t, err
+	}
+	if upper {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// applyPostStatusFilter restricts a post listing to the status given by the
+// status query parameter. Lists only show published posts unless the
+// signed-in user asks for a status; other statuses are then restricted to
+// posts they are an author of (or everyone's, for admins). It writes an error response
+// and returns false on invalid input.
+func applyPostStatusFilter(c *gin.Context, query *gorm.DB) (*gorm.DB, bool) {
+	status := models.StatusPublished
+	if value := c.Query("status"); value != "" {
+		var valid bool
+		if status, valid = parsePostStatus(value); !valid {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("invalid status"))
+			return nil, false
+		}
+	}
+	query = query.Where("posts.status = ?", status)
+	if status != models.StatusPublished {
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, utils.ErrorResponse("sign in to list unpublished posts"))
+			return nil, false
+		}
+		userRole, _ := c.Get("userRole")
+		if userRole.(string) != "admin" {
+			query = query.Where(authoredPostsSQL, userID.(uint))
+		}
+	}
+	return query, true
+}
+
+// applyPostFilters narrows a post listing by the optional query parameters
+// author, tag, hasImage, from and to. It writes a 400 response and returns
+// false on invalid input.
+func applyPostFilters(c *gin.Context, query *gorm.DB) (*gorm.DB, bool) {
+	if author := strings.TrimSpace(c.Query("author")); author != "" {
+		query = query.Where(`posts.id IN (SELECT post_authors.post_id FROM post_authors
+			JOIN users ON users.id = post_authors.user_id
+			WHERE users.username = ? AND post_authors.accepted_at IS NOT NULL)`, author)
+	}
+
+	if tag := strings.Join(strings.Fields(strings.ToLower(c.Query("tag"))), " "); tag != "" {
+		encoded, _ := json.Marshal([]string{tag})
+		query = query.Where("posts.tags @> ?::jsonb", string(encoded))
+	}
+
+	switch c.Query("hasImage") {
+	case "":
+	case "true":
+		query = query.Where("posts.image_url IS NOT NULL AND posts.image_url <> ''")
+	case "false":
+		query = query.Where("posts.image_url IS NULL OR posts.image_url = ''")
+	default:
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("hasImage must be true or false"))
+		return nil, false
+	}
+
+	// Published posts are filtered by publication date; drafts and scheduled
+	// posts have none, so their creation date is used.
+	dateColumn := "COALESCE(posts.published_at, posts.created_at)"
+	if value := c.Query("from"); value != "" {
+		from, err := parseDateParam(value, false)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("from must be a date (YYYY-MM-DD) or RFC 3339 timestamp"))
+			return nil, false
+		}
+		query = query.Where(dateColumn+" >= ?", from)
+	}
+	if value := c.Query("to"); value != "" {
+		to, err := parseDateParam(value, true)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("to must be a date (YYYY-MM-DD) or RFC 3339 timestamp"))
+			return nil, false
+		}
+		query = query.Where(dateColumn+" < ?", to)
+	}
+
+	return query, true
+}
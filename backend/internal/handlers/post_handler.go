@@ -2,13 +2,21 @@ package handlers
 
 import (
 	"errors"
+	"fmt"
+	"log"
 	"net/http"
+	"strings"
+	"time"
+	"travel-blog-backend/internal/config"
+	"travel-blog-backend/internal/content"
 	"travel-blog-backend/internal/database"
 	"travel-blog-backend/internal/models"
 	"travel-blog-backend/internal/utils"
+	"travel-blog-backend/internal/views"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type PostHandler struct{}
@@ -20,40 +28,194 @@ func NewPostHandler() *PostHandler {
 type CreatePostRequest struct {
 	Title    string  `json:"title" binding:"required"`
 	Content  string  `json:"content" binding:"required"`
+	ContentFormat string `json:"contentFormat"`
+	Language string  `json:"language"`
 	Excerpt  *string `json:"excerpt"`
 	ImageURL *string `json:"imageUrl"`
-	Status   string  `json:"status"`
+	Status    string     `json:"status"`
+	Visibility string    `json:"visibility"`
+	CommentModeration string `json:"commentModeration"`
+	PublishAt *time.Time `json:"publishAt"`
+	TripID    *uint      `json:"tripId"`
+	PlaceID   *uint      `json:"placeId"`
+	Tags      []string   `json:"tags"`
 }
 
 type UpdatePostRequest struct {
 	Title    *string `json:"title"`
 	Content  *string `json:"content"`
+	ContentFormat *string `json:"contentFormat"`
+	Language *string `json:"language"`
 	Excerpt  *string `json:"excerpt"`
 	ImageURL *string `json:"imageUrl"`
-	Status   *string `json:"status"`
+	Status    *string    `json:"status"`
+	Visibility *string   `json:"visibility"`
+	CommentModeration *string `json:"commentModeration"`
+	PublishAt *time.Time `json:"publishAt"`
+	TripID    *uint      `json:"tripId"`
+	PlaceID   *uint      `json:"placeId"`
+	Tags      *[]string  `json:"tags"`
 }
 
+// PostListResponse is a page of posts. Cursor-paginated lists leave Page
+// unset and link their neighbours through NextCursor and PrevCursor.
 type PostListResponse struct {
 	Posts      []models.Post `json:"posts"`
 	Total      int64         `json:"total"`
-	Page       int           `json:"page"`
+	Page       int           `json:"page,omitempty"`
 	PageSize   int           `json:"pageSize"`
 	TotalPages int           `json:"totalPages"`
+	NextCursor *string       `json:"nextCursor,omitempty"`
+	PrevCursor *string       `json:"prevCursor,omitempty"`
 }
 
 
-func (h *PostHandler) checkPostPermission(c *gin.Context, post models.Post) bool {
+const (
+	maxTags      = 10
+	maxTagLength = 40
+)
+
+// normalizeTags lowercases and trims tags, collapses inner whitespace and
+// drops duplicates while keeping the author's order.
+func normalizeTags(tags []string) (models.StringList, error) {
+	normalized := make(models.StringList, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.Join(strings.Fields(strings.ToLower(tag)), " ")
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if len([]rune(tag)) > maxTagLength {
+			return nil, fmt.Errorf("tags must not be longer than %d characters", maxTagLength)
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > maxTags {
+		return nil, fmt.Errorf("a post can have at most %d tags", maxTags)
+	}
+	return normalized, nil
+}
+
+func parsePostStatus(value string) (models.PostStatus, bool) {
+	switch models.PostStatus(value) {
+	case "", models.StatusPublished:
+		return models.StatusPublished, true
+	case models.StatusDraft:
+		return models.StatusDraft, true
+	case models.StatusScheduled:
+		return models.StatusScheduled, true
+	}
+	return "", false
+}
+
+// rerenderPost refreshes the rendered content fields of a post after its
+// content, format or excerpt changed.
+func rerenderPost(tx *gorm.DB, post *models.Post) error {
+	if err := tx.First(post, post.ID).Error; err != nil {
+		return err
+	}
+	if err := content.ApplyToPost(post); err != nil {
+		return err
+	}
+	return tx.Model(post).Updates(map[string]interface{}{
+		"content_html":      post.ContentHTML,
+		"toc":               post.TOC,
+		"reading_time":      post.ReadingTime,
+		"excerpt":           post.Excerpt,
+		"excerpt_generated": post.ExcerptGenerated,
+	}).Error
+}
+
+// checkPostPermission allows admins and every author of post, owners and
+// editors alike, to edit it.
+func checkPostPermission(c *gin.Context, post models.Post) bool {
+	return checkPostRole(c, post, false)
+}
+
+// checkPostOwnership allows admins and owners of post to delete it and
+// manage its authors.
+func checkPostOwnership(c *gin.Context, post models.Post) bool {
+	return checkPostRole(c, post, true)
+}
+
+func checkPostRole(c *gin.Context, post models.Post, ownerOnly bool) bool {
 	userID, _ := c.Get("userID")
 	userRole, _ := c.Get("userRole")
-	if post.UserID != userID.(uint) && userRole.(string) != "admin" {
+	if userRole.(string) == "admin" {
+		return true
+	}
+
+	role, err := postAuthorRole(post, userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to check permission"))
+		return false
+	}
+	if role == "" || (ownerOnly && role != models.PostAuthorOwner) {
 		c.JSON(http.StatusForbidden, utils.ErrorResponse("permission denied"))
 		return false
 	}
 	return true
 }
 
+// checkTripOwnership reports whether the current user may attach posts to
+// the given trip. A zero trip ID detaches the post and is always allowed.
+func (h *PostHandler) checkTripOwnership(c *gin.Context, tripID uint) bool {
+	if tripID == 0 {
+		return true
+	}
+
+	var trip models.Trip
+	if err := database.DB.Select("id", "user_id").First(&trip, tripID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("trip not found"))
+		} else {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get trip"))
+		}
+		return false
+	}
+
+	userID, _ := c.Get("userID")
+	userRole, _ := c.Get("userRole")
+	if trip.UserID != userID.(uint) && userRole.(string) != "admin" {
+		c.JSON(http.StatusForbidden, utils.ErrorResponse("permission denied for trip"))
+		return false
+	}
+	return true
+}
+
+func (h *PostHandler) checkPlaceExists(c *gin.Context, placeID uint) bool {
+	var count int64
+	if err := database.DB.Model(&models.Place{}).Where("id = ?", placeID).Count(&count).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get place"))
+		return false
+	}
+	if count == 0 {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("place not found"))
+		return false
+	}
+	return true
+}
+
+// decoratePosts fills in the computed, per-request fields of posts that are
+// about to be returned: the translation the reader prefers, their authors,
+// reaction counts and, for signed-in readers, whether they bookmarked each
+// post.
+func decoratePosts(c *gin.Context, posts []models.Post) error {
+	if err := applyTranslations(c, posts); err != nil {
+		return err
+	}
+	if err := attachAuthors(posts); err != nil {
+		return err
+	}
+	if err := attachReactionCounts(posts); err != nil {
+		return err
+	}
+	return attachBookmarkFlags(c, posts)
+}
+
 func (h *PostHandler) GetPosts(c *gin.Context) {
-	h.getPostsWithFilter(c, database.DB.Model(&models.Post{}).Where("status = ?", models.StatusPublished))
+	h.getPostsWithFilter(c, database.DB.Model(&models.Post{}), "posts.published_at DESC, posts.id DESC")
 }
 
 func (h *PostHandler) GetPost(c *gin.Context) {
@@ -63,7 +225,7 @@ func (h *PostHandler) GetPost(c *gin.Context) {
 	}
 
 	var post models.Post
-	if err := database.DB.Preload("User").First(&post, id).Error; err != nil {
+	if err := database.DB.Preload("User").Preload("Place").First(&post, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			c.JSON(http.StatusNotFound, utils.ErrorResponse("post not found"))
 		} else {
@@ -72,7 +234,142 @@ func (h *PostHandler) GetPost(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, utils.SuccessResponse(post))
+	if !checkPostVisible(c, post) {
+		return
+	}
+
+	if post.Status == models.StatusPublished {
+		h.recordView(c, post)
+	}
+
+	posts := []models.Post{post}
+	if err := decoratePosts(c, posts); err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get post"))
+		return
+	}
+	if err := attachSeriesNavigation(c, &posts[0]); err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get post"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(posts[0]))
+}
+
+// recordView counts the request as a view of post unless it comes from a bot
+// or from the author. Visitors are identified by a daily hash; raw IP
+// addresses are never stored.
+func (h *PostHandler) recordView(c *gin.Context, post models.Post) {
+	userAgent := c.Request.UserAgent()
+	if views.IsBot(userAgent) {
+		return
+	}
+
+	identity := c.ClientIP() + "|" + userAgent
+	if userID, exists := c.Get("userID"); exists {
+		if userID.(uint) == post.UserID {
+			return
+		}
+		identity = fmt.Sprintf("user:%d", userID.(uint))
+	}
+
+	now := time.Now()
+	visitor, err := views.Visitor(c.Request.Context(), identity, now)
+	if err != nil {
+		log.Printf("Failed to identify visitor of post %d: %v", post.ID, err)
+		return
+	}
+	views.Record(post.ID, visitor, now)
+}
+
+// Weights of the signals that make a post trend. Each event's contribution
+// halves every TrendingHalfLife hours.
+const (
+	trendingViewWeight     = 1.0
+	trendingReactionWeight = 5.0
+	trendingCommentWeight  = 10.0
+	trendingWindowDays     = 30
+)
+
+const trendingSQL = `WITH scores AS (
+	SELECT post_id, SUM(score) AS score FROM (
+		SELECT post_id, views * @view_weight * power(0.5, EXTRACT(EPOCH FROM now() - (day::timestamp AT TIME ZONE 'UTC' + interval '12 hours')) / 3600 / @half_life) AS score
+		FROM post_view_daily WHERE day >= @since::date
+		UNION ALL
+		SELECT post_id, @reaction_weight * power(0.5, EXTRACT(EPOCH FROM now() - created_at) / 3600 / @half_life)
+		FROM post_reactions WHERE created_at >= @since
+		UNION ALL
+		SELECT post_id, @comment_weight * power(0.5, EXTRACT(EPOCH FROM now() - created_at) / 3600 / @half_life)
+		FROM comments WHERE created_at >= @since AND deleted_at IS NULL AND status = 'approved'
+	) AS events
+	GROUP BY post_id
+)
+SELECT posts.id, COUNT(*) OVER () AS total
+FROM scores JOIN posts ON posts.id = scores.post_id
+WHERE posts.status = @status AND posts.visibility = @visibility AND posts.hidden_at IS NULL AND posts.deleted_at IS NULL
+ORDER BY scores.score DESC, posts.id DESC
+LIMIT @limit OFFSET @offset`
+
+// GetTrendingPosts ranks public published posts by their recent views,
+// reactions and comments, with older activity decaying exponentially.
+func (h *PostHandler) GetTrendingPosts(c *gin.Context) {
+	page, pageSize := utils.ParsePagination(c, 1, 10, 50)
+
+	var rows []struct {
+		ID    uint
+		Total int64
+	}
+	if err := database.DB.Raw(trendingSQL, map[string]interface{}{
+		"view_weight":     trendingViewWeight,
+		"reaction_weight": trendingReactionWeight,
+		"comment_weight":  trendingCommentWeight,
+		"half_life":       float64(config.AppConfig.TrendingHalfLife),
+		"since":           time.Now().UTC().AddDate(0, 0, -trendingWindowDays),
+		"status":          models.StatusPublished,
+		"visibility":      models.VisibilityPublic,
+		"limit":           pageSize,
+		"offset":          (page - 1) * pageSize,
+	}).Scan(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get trending posts"))
+		return
+	}
+
+	var total int64
+	ids := make([]uint, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+		total = row.Total
+	}
+
+	posts := make([]models.Post, 0, len(ids))
+	if len(ids) > 0 {
+		var found []models.Post
+		if err := database.DB.Preload("User").Where("id IN ?", ids).Find(&found).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get trending posts"))
+			return
+		}
+		byID := make(map[uint]models.Post, len(found))
+		for _, post := range found {
+			byID[post.ID] = post
+		}
+		for _, id := range ids {
+			if post, ok := byID[id]; ok {
+				posts = append(posts, post)
+			}
+		}
+	}
+
+	if err := decoratePosts(c, posts); err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get trending posts"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(PostListResponse{
+		Posts:      posts,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: utils.CalculateTotalPages(total, pageSize),
+	}))
 }
 
 func (h *PostHandler) CreatePost(c *gin.Context) {
@@ -88,26 +385,115 @@ func (h *PostHandler) CreatePost(c *gin.Context) {
 		return
 	}
 
-	status := models.StatusPublished
-	if req.Status == "draft" {
-		status = models.StatusDraft
+	status, valid := parsePostStatus(req.Status)
+	if !valid {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("invalid status"))
+		return
+	}
+
+	visibility, valid := parsePostVisibility(req.Visibility)
+	if !valid {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("invalid visibility"))
+		return
+	}
+
+	var publishAt, publishedAt *time.Time
+	switch status {
+	case models.StatusScheduled:
+		if req.PublishAt == nil || !req.PublishAt.After(time.Now()) {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("publishAt must be in the future for scheduled posts"))
+			return
+		}
+		publishAt = req.PublishAt
+	case models.StatusPublished:
+		now := time.Now()
+		publishedAt = &now
+	}
+
+	format, valid := content.ParseFormat(req.ContentFormat)
+	if !valid {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("invalid contentFormat"))
+		return
+	}
+
+	language, valid := parsePostLanguage(req.Language)
+	if !valid {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("invalid language"))
+		return
+	}
+
+	commentModeration, valid := parseCommentModeration(req.CommentModeration)
+	if !valid {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("commentModeration must be one of open, first_time, all"))
+		return
+	}
+
+	var tripID *uint
+	if req.TripID != nil && *req.TripID != 0 {
+		if !h.checkTripOwnership(c, *req.TripID) {
+			return
+		}
+		tripID = req.TripID
+	}
+
+	var placeID *uint
+	if req.PlaceID != nil && *req.PlaceID != 0 {
+		if !h.checkPlaceExists(c, *req.PlaceID) {
+			return
+		}
+		placeID = req.PlaceID
+	}
+
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
 	}
 
 	post := models.Post{
-		Title:    req.Title,
-		Content:  req.Content,
-		Excerpt:  req.Excerpt,
-		ImageURL: req.ImageURL,
-		UserID:   userID.(uint),
-		Status:   status,
+		Title:       req.Title,
+		Content:     req.Content,
+		ContentFormat: format,
+		Language:    language,
+		Excerpt:     req.Excerpt,
+		ImageURL:    req.ImageURL,
+		UserID:      userID.(uint),
+		Status:      status,
+		Visibility:  visibility,
+		CommentModeration: commentModeration,
+		PublishAt:   publishAt,
+		PublishedAt: publishedAt,
+		TripID:      tripID,
+		PlaceID:     placeID,
+		Tags:        tags,
 	}
 
-	if err := database.DB.Create(&post).Error; err != nil {
+	if err := content.ApplyToPost(&post); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("failed to render content"))
+		return
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&post).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&models.PostAuthor{
+			PostID:     post.ID,
+			UserID:     post.UserID,
+			Role:       models.PostAuthorOwner,
+			AcceptedAt: &post.CreatedAt,
+		}).Error; err != nil {
+			return err
+		}
+		return recordRevision(tx, post, post.UserID, changedRevisionFields(models.Post{}, &post.Title, &post.Content, post.Excerpt, post.ImageURL), nil)
+	})
+	if err != nil {
 		c.JSON(http.StatusBadRequest, utils.ErrorResponse("failed to create post"))
 		return
 	}
+	invalidateRelated()
 
-	database.DB.Preload("User").First(&post, post.ID)
+	database.DB.Preload("User").Preload("Place").First(&post, post.ID)
 	c.JSON(http.StatusCreated, utils.SuccessResponse(post))
 }
 
@@ -133,7 +519,7 @@ func (h *PostHandler) UpdatePost(c *gin.Context) {
 		return
 	}
 
-	if !h.checkPostPermission(c, post) {
+	if !checkPostPermission(c, post) {
 		return
 	}
 
@@ -144,26 +530,151 @@ func (h *PostHandler) UpdatePost(c *gin.Context) {
 	if req.Content != nil {
 		updates["content"] = *req.Content
 	}
+	if req.ContentFormat != nil {
+		format, valid := content.ParseFormat(*req.ContentFormat)
+		if !valid {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("invalid contentFormat"))
+			return
+		}
+		updates["content_format"] = format
+	}
+	if req.Language != nil {
+		language, valid := parsePostLanguage(*req.Language)
+		if !valid {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("invalid language"))
+			return
+		}
+		if language != post.Language {
+			var translations int64
+			if err := database.DB.Model(&models.PostTranslation{}).Where("post_id = ? AND language = ?", post.ID, language).Count(&translations).Error; err != nil {
+				c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to update post"))
+				return
+			}
+			if translations > 0 {
+				c.JSON(http.StatusConflict, utils.ErrorResponse("the post already has a translation in this language"))
+				return
+			}
+		}
+		updates["language"] = language
+	}
+	if req.CommentModeration != nil {
+		commentModeration, valid := parseCommentModeration(*req.CommentModeration)
+		if !valid {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("commentModeration must be one of open, first_time, all"))
+			return
+		}
+		updates["comment_moderation"] = commentModeration
+	}
 	if req.Excerpt != nil {
-		updates["excerpt"] = req.Excerpt
+		if strings.TrimSpace(*req.Excerpt) == "" {
+			updates["excerpt"] = nil
+			updates["excerpt_generated"] = true
+		} else {
+			updates["excerpt"] = req.Excerpt
+			updates["excerpt_generated"] = false
+		}
 	}
 	if req.ImageURL != nil {
 		updates["image_url"] = req.ImageURL
 	}
-	if req.Status != nil {
-		if *req.Status == "draft" {
-			updates["status"] = models.StatusDraft
+	if req.Status != nil || req.PublishAt != nil {
+		status := post.Status
+		if req.Status != nil {
+			var valid bool
+			if status, valid = parsePostStatus(*req.Status); !valid {
+				c.JSON(http.StatusBadRequest, utils.ErrorResponse("invalid status"))
+				return
+			}
+		}
+
+		switch status {
+		case models.StatusScheduled:
+			publishAt := post.PublishAt
+			if req.PublishAt != nil {
+				publishAt = req.PublishAt
+			}
+			if publishAt == nil || !publishAt.After(time.Now()) {
+				c.JSON(http.StatusBadRequest, utils.ErrorResponse("publishAt must be in the future for scheduled posts"))
+				return
+			}
+			updates["publish_at"] = *publishAt
+			updates["published_at"] = nil
+		case models.StatusPublished:
+			if post.Status != models.StatusPublished {
+				updates["published_at"] = time.Now()
+			}
+			updates["publish_at"] = nil
+		case models.StatusDraft:
+			updates["publish_at"] = nil
+		}
+		updates["status"] = status
+	}
+	if req.Visibility != nil {
+		visibility, valid := parsePostVisibility(*req.Visibility)
+		if !valid {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("invalid visibility"))
+			return
+		}
+		updates["visibility"] = visibility
+	}
+	if req.TripID != nil {
+		if !h.checkTripOwnership(c, *req.TripID) {
+			return
+		}
+		if *req.TripID == 0 {
+			updates["trip_id"] = nil
+		} else {
+			updates["trip_id"] = *req.TripID
+		}
+	}
+	if req.PlaceID != nil {
+		if *req.PlaceID == 0 {
+			updates["place_id"] = nil
+		} else if !h.checkPlaceExists(c, *req.PlaceID) {
+			return
 		} else {
-			updates["status"] = models.StatusPublished
+			updates["place_id"] = *req.PlaceID
 		}
 	}
+	if req.Tags != nil {
+		tags, err := normalizeTags(*req.Tags)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+			return
+		}
+		updates["tags"] = tags
+	}
 
-	if err := database.DB.Model(&post).Updates(updates).Error; err != nil {
+	userID, _ := c.Get("userID")
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&post, post.ID).Error; err != nil {
+			return err
+		}
+		changed := changedRevisionFields(post, req.Title, req.Content, req.Excerpt, req.ImageURL)
+
+		if err := tx.Model(&post).Updates(updates).Error; err != nil {
+			return err
+		}
+		if req.Content != nil || req.ContentFormat != nil || req.Excerpt != nil {
+			if err := rerenderPost(tx, &post); err != nil {
+				return err
+			}
+		}
+		if len(changed) == 0 {
+			return nil
+		}
+		if err := tx.First(&post, post.ID).Error; err != nil {
+			return err
+		}
+		return recordRevision(tx, post, userID.(uint), changed, nil)
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to update post"))
 		return
 	}
+	invalidateRelated()
 
-	database.DB.Preload("User").First(&post, post.ID)
+	database.DB.Preload("User").Preload("Place").First(&post, post.ID)
 	c.JSON(http.StatusOK, utils.SuccessResponse(post))
 }
 
@@ -183,7 +694,7 @@ func (h *PostHandler) DeletePost(c *gin.Context) {
 		return
 	}
 
-	if !h.checkPostPermission(c, post) {
+	if !checkPostOwnership(c, post) {
 		return
 	}
 
@@ -191,6 +702,7 @@ func (h *PostHandler) DeletePost(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to delete post"))
 		return
 	}
+	invalidateRelated()
 
 	c.JSON(http.StatusOK, utils.MessageResponse("Post deleted successfully"))
 }
@@ -200,32 +712,134 @@ func (h *PostHandler) GetPostsByUser(c *gin.Context) {
 	if !ok {
 		return
 	}
-	h.getPostsWithFilter(c, database.DB.Model(&models.Post{}).Where("status = ? AND user_id = ?", models.StatusPublished, userID))
+	h.getPostsWithFilter(c, database.DB.Model(&models.Post{}).Where(authoredPostsSQL, userID), "posts.published_at DESC, posts.id DESC")
 }
 
-func (h *PostHandler) getPostsWithFilter(c *gin.Context, query *gorm.DB) {
+func (h *PostHandler) GetPostsByTrip(c *gin.Context) {
+	tripID, ok := utils.ParseUintParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+	h.getPostsWithFilter(c, database.DB.Model(&models.Post{}).Where("posts.trip_id = ?", tripID), "posts.published_at ASC, posts.id ASC")
+}
+
+func (h *PostHandler) GetPostsByPlace(c *gin.Context) {
+	placeID, ok := utils.ParseUintParam(c, "id", "Invalid place ID")
+	if !ok {
+		return
+	}
+	h.getPostsWithFilter(c, database.DB.Model(&models.Post{}).Where("posts.place_id IN ("+placeDescendantsSQL+")", placeID), "posts.published_at DESC, posts.id DESC")
+}
+
+// GetMyPosts lists the posts the current user is an author of in every
+// status, including drafts and scheduled posts. The status parameter takes a comma-separated
+// list of statuses to narrow it down.
+func (h *PostHandler) GetMyPosts(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	query := database.DB.Model(&models.Post{}).Where(authoredPostsSQL, userID.(uint))
+
+	if value := c.Query("status"); value != "" {
+		var statuses []models.PostStatus
+		for _, part := range strings.Split(value, ",") {
+			part = strings.TrimSpace(part)
+			status, valid := parsePostStatus(part)
+			if !valid || part == "" {
+				c.JSON(http.StatusBadRequest, utils.ErrorResponse("invalid status"))
+				return
+			}
+			statuses = append(statuses, status)
+		}
+		query = query.Where("posts.status IN ?", statuses)
+	}
+
+	h.listPosts(c, query, "posts.updated_at DESC, posts.id DESC")
+}
+
+// GetFeed lists the posts of the authors the current user follows,
+// including their followers-only posts.
+func (h *PostHandler) GetFeed(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	h.getPostsWithFilter(c, database.DB.Model(&models.Post{}).
+		Where("posts.user_id IN (SELECT following_id FROM follows WHERE follower_id = ?)", userID.(uint)),
+		"posts.published_at DESC, posts.id DESC")
+}
+
+// getPostsWithFilter lists the posts matched by query in the status picked
+// by the status query parameter, published by default.
+func (h *PostHandler) getPostsWithFilter(c *gin.Context, query *gorm.DB, defaultOrder string) {
+	query, ok := applyPostStatusFilter(c, query)
+	if !ok {
+		return
+	}
+	h.listPosts(c, query, defaultOrder)
+}
+
+// listPosts lists the posts matched by query, narrowed by the filter query
+// parameters and sorted by defaultOrder unless the client picks another
+// sort. Passing a cursor switches to keyset pagination by creation time,
+// which stays stable while posts are being added.
+func (h *PostHandler) listPosts(c *gin.Context, query *gorm.DB, defaultOrder string) {
 	page, pageSize := utils.ParsePagination(c, 1, 10, 100)
 
+	query, ok := applyPostFilters(c, query.Scopes(visiblePostsScope(c, true)))
+	if !ok {
+		return
+	}
+
+	keyset, cursorMode, ok := parseKeysetRequest(c, pageSize)
+	if !ok {
+		return
+	}
+	if cursorMode {
+		if sort := c.Query("sort"); sort != "" && sort != "created" {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("cursor pagination only supports sort=created"))
+			return
+		}
+	}
+
 	var posts []models.Post
 	var total int64
 
 	query.Count(&total)
 
-	offset := (page - 1) * pageSize
-	if err := query.Preload("User").
-		Order("created_at DESC").
-		Limit(pageSize).
-		Offset(offset).
-		Find(&posts).Error; err != nil {
+	response := PostListResponse{
+		Total:      total,
+		PageSize:   pageSize,
+		TotalPages: utils.CalculateTotalPages(total, pageSize),
+	}
+	if cursorMode {
+		next, prev, err := keysetPage(query.Preload("User"), "posts", keyset, &posts, func(p models.Post) (time.Time, uint) {
+			return p.CreatedAt, p.ID
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get posts"))
+			return
+		}
+		response.NextCursor, response.PrevCursor = next, prev
+	} else {
+		order, err := postSortOrder(c, defaultOrder)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+			return
+		}
+
+		offset := (page - 1) * pageSize
+		if err := query.Preload("User").
+			Order(order).
+			Limit(pageSize).
+			Offset(offset).
+			Find(&posts).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get posts"))
+			return
+		}
+		response.Page = page
+	}
+
+	if err := decoratePosts(c, posts); err != nil {
 		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get posts"))
 		return
 	}
 
-	c.JSON(http.StatusOK, utils.SuccessResponse(PostListResponse{
-		Posts:      posts,
-		Total:      total,
-		Page:       page,
-		PageSize:   pageSize,
-		TotalPages: utils.CalculateTotalPages(total, pageSize),
-	}))
+	response.Posts = posts
+	c.JSON(http.StatusOK, utils.SuccessResponse(response))
 }
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+	"travel-blog-backend/internal/config"
+	"travel-blog-backend/internal/database"
+	"travel-blog-backend/internal/models"
+	"travel-blog-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxPreviewLinkHours bounds how long a preview link can stay valid.
+const maxPreviewLinkHours = 30 * 24
+
+type PreviewHandler struct{}
+
+func NewPreviewHandler() *PreviewHandler {
+	return &PreviewHandler{}
+}
+
+type CreatePreviewLinkRequest struct {
+	ExpiresInHours *int `json:"expiresInHours"`
+}
+
+type PreviewLinkResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+func (h *PreviewHandler) findOwnPost(c *gin.Context, post *models.Post) bool {
+	id, ok := utils.ParseUintParam(c, "id", "Invalid post ID")
+	if !ok {
+		return false
+	}
+
+	if err := database.DB.Select("id", "user_id", "preview_version").First(post, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("post not found"))
+		} else {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get post"))
+		}
+		return false
+	}
+	return checkPostPermission(c, *post)
+}
+
+// CreatePreviewLink issues a signed token that lets anyone holding it read
+// the post, whatever its status, until it expires or is revoked.
+func (h *PreviewHandler) CreatePreviewLink(c *gin.Context) {
+	var post models.Post
+	if !h.findOwnPost(c, &post) {
+		return
+	}
+
+	// The body is optional; without one the link gets the default lifetime.
+	var req CreatePreviewLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request data: "+err.Error()))
+		return
+	}
+
+	hours := config.AppConfig.PreviewLinkExpiry
+	if req.ExpiresInHours != nil {
+		hours = *req.ExpiresInHours
+		if hours < 1 || hours > maxPreviewLinkHours {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("expiresInHours must be between 1 and 720"))
+			return
+		}
+	}
+
+	expiresAt := time.Now().Add(time.Duration(hours) * time.Hour).Truncate(time.Second)
+	token, err := utils.GeneratePreviewToken(post.ID, post.PreviewVersion, expiresAt)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to create preview link"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.SuccessResponse(PreviewLinkResponse{
+		Token:     token,
+		ExpiresAt: expiresAt,
+	}))
+}
+
+// RevokePreviewLinks invalidates every preview link issued for the post.
+func (h *PreviewHandler) RevokePreviewLinks(c *gin.Context) {
+	var post models.Post
+	if !h.findOwnPost(c, &post) {
+		return
+	}
+
+	if err := database.DB.Model(&post).
+		UpdateColumn("preview_version", gorm.Expr("preview_version + 1")).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to revoke preview links"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.MessageResponse("Preview links revoked"))
+}
+
+// GetPreview returns the post a preview token was issued for. Previews are
+// not counted as views.
+func (h *PreviewHandler) GetPreview(c *gin.Context) {
+	claims, err := utils.ValidatePreviewToken(c.Param("token"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Invalid or expired preview link"))
+		return
+	}
+
+	var post models.Post
+	if err := database.DB.Preload("User").Preload("Place").First(&post, claims.PostID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("post not found"))
+		} else {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get post"))
+		}
+		return
+	}
+	if post.PreviewVersion != claims.Version {
+		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Invalid or expired preview link"))
+		return
+	}
+
+	posts := []models.Post{post}
+	if err := decoratePosts(c, posts); err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get post"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(posts[0]))
+}
@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"travel-blog-backend/internal/database"
+	"travel-blog-backend/internal/models"
+	"travel-blog-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReactionHandler struct{}
+
+func NewReactionHandler() *ReactionHandler {
+	return &ReactionHandler{}
+}
+
+// reactionTypes lists the reactions users may leave on a post, in display
+// order.
+var reactionTypes = []models.ReactionType{
+	models.ReactionLike,
+	models.ReactionLove,
+	models.ReactionWow,
+	models.ReactionLaugh,
+	models.ReactionSad,
+}
+
+type ReactionSummaryResponse struct {
+	PostID      uint                          `json:"postId"`
+	Reactions   map[models.ReactionType]int64 `json:"reactions"`
+	MyReactions []models.ReactionType         `json:"myReactions"`
+}
+
+type ReactionListResponse struct {
+	Reactions  []models.PostReaction `json:"reactions"`
+	Total      int64                 `json:"total"`
+	Page       int                   `json:"page"`
+	PageSize   int                   `json:"pageSize"`
+	TotalPages int                   `json:"totalPages"`
+}
+
+func parseReactionType(value string) (models.ReactionType, bool) {
+	for _, reaction := range reactionTypes {
+		if string(reaction) == value {
+			return reaction, true
+		}
+	}
+	return "", false
+}
+
+// attachReactionCounts fills in the reaction counts of every post with a
+// single grouped query. Counts are always derived from the reaction rows, so
+// they cannot drift under concurrent toggles.
+func attachReactionCounts(posts []models.Post) error {
+	if len(posts) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(posts))
+	index := make(map[uint]int, len(posts))
+	for i := range posts {
+		ids[i] = posts[i].ID
+		index[posts[i].ID] = i
+		posts[i].Reactions = make(map[models.ReactionType]int64, len(reactionTypes))
+		for _, reaction := range reactionTypes {
+			posts[i].Reactions[reaction] = 0
+		}
+	}
+
+	var rows []struct {
+		PostID uint
+		Type   models.ReactionType
+		Count  int64
+	}
+	if err := database.DB.Model(&models.PostReaction{}).
+		Select("post_id, type, COUNT(*) AS count").
+		Where("post_id IN ?", ids).
+		Group("post_id, type").
+		Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		posts[index[row.PostID]].Reactions[row.Type] = row.Count
+	}
+	return nil
+}
+
+func (h *ReactionHandler) findPost(c *gin.Context, post *models.Post) bool {
+	id, ok := utils.ParseUintParam(c, "id", "Invalid post ID")
+	if !ok {
+		return false
+	}
+
+	if err := database.DB.Select(postVisibilityColumns).First(post, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("post not found"))
+		} else {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get post"))
+		}
+		return false
+	}
+	return checkPostVisible(c, *post)
+}
+
+// reactionTarget resolves the post and reaction type of a toggle request.
+func (h *ReactionHandler) reactionTarget(c *gin.Context) (models.Post, models.ReactionType, bool) {
+	var post models.Post
+	if !h.findPost(c, &post) {
+		return post, "", false
+	}
+
+	reaction, ok := parseReactionType(c.Param("type"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid reaction type"))
+		return post, "", false
+	}
+	return post, reaction, true
+}
+
+func (h *ReactionHandler) summary(c *gin.Context, postID uint) {
+	userID, _ := c.Get("userID")
+
+	posts := []models.Post{{ID: postID}}
+	if err := attachReactionCounts(posts); err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get reactions"))
+		return
+	}
+
+	mine := make([]models.ReactionType, 0)
+	if err := database.DB.Model(&models.PostReaction{}).
+		Where("post_id = ? AND user_id = ?", postID, userID.(uint)).
+		Order("created_at ASC").
+		Pluck("type", &mine).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get reactions"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(ReactionSummaryResponse{
+		PostID:      postID,
+		Reactions:   posts[0].Reactions,
+		MyReactions: mine,
+	}))
+}
+
+// AddReaction records the current user's reaction. Repeating the request is
+// a no-op. Only published posts accept new reactions.
+func (h *ReactionHandler) AddReaction(c *gin.Context) {
+	post, reaction, ok := h.reactionTarget(c)
+	if !ok {
+		return
+	}
+
+	if post.Status != models.StatusPublished {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("only published posts can be reacted to"))
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	row := models.PostReaction{PostID: post.ID, UserID: userID.(uint), Type: reaction}
+	if err := database.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&row).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to add reaction"))
+		return
+	}
+
+	h.summary(c, post.ID)
+}
+
+// RemoveReaction withdraws the current user's reaction, also from posts that
+// have since been unpublished. Removing a reaction that does not exist is a
+// no-op.
+func (h *ReactionHandler) RemoveReaction(c *gin.Context) {
+	post, reaction, ok := h.reactionTarget(c)
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	if err := database.DB.
+		Where("post_id = ? AND user_id = ? AND type = ?", post.ID, userID.(uint), reaction).
+		Delete(&models.PostReaction{}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to remove reaction"))
+		return
+	}
+
+	h.summary(c, post.ID)
+}
+
+func (h *ReactionHandler) GetReactions(c *gin.Context) {
+	var post models.Post
+	if !h.findPost(c, &post) {
+		return
+	}
+
+	page, pageSize := utils.ParsePagination(c, 1, 20, 100)
+
+	var reactions []models.PostReaction
+	var total int64
+
+	query := database.DB.Model(&models.PostReaction{}).Where("post_id = ?", post.ID)
+	if value := c.Query("type"); value != "" {
+		reaction, ok := parseReactionType(value)
+		if !ok {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid reaction type"))
+			return
+		}
+		query = query.Where("type = ?", reaction)
+	}
+	query.Count(&total)
+
+	offset := (page - 1) * pageSize
+	if err := query.Preload("User").
+		Order("created_at DESC").
+		Limit(pageSize).
+		Offset(offset).
+		Find(&reactions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get reactions"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(ReactionListResponse{
+		Reactions:  reactions,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: utils.CalculateTotalPages(total, pageSize),
+	}))
+}
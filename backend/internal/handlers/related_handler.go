@@ -0,0 +1,320 @@
+package handlers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"travel-blog-backend/internal/database"
+	"travel-blog-backend/internal/gpx"
+	"travel-blog-backend/internal/models"
+	"travel-blog-backend/internal/utils"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	// relatedCandidates bounds how many recent posts are scored per request.
+	relatedCandidates = 500
+	maxRelatedPosts   = 20
+	relatedCacheTTL   = 15 * time.Minute
+	relatedCacheSize  = 1000
+
+	// proximityScaleKm is the distance at which the proximity score drops to
+	// about a third of its maximum.
+	proximityScaleKm = 200.0
+)
+
+// relatedVisibleSQL matches the posts every reader may be recommended.
+const relatedVisibleSQL = "posts.status = ? AND posts.visibility = ? AND posts.hidden_at IS NULL"
+
+// Weights of the signals that make two posts related.
+const (
+	relatedTagWeight       = 4.0
+	relatedProximityWeight = 3.0
+	relatedTextWeight      = 3.0
+	relatedTripWeight      = 2.5
+	relatedAuthorWeight    = 1.0
+)
+
+var relatedStopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "that": true, "this": true,
+	"from": true, "are": true, "was": true, "were": true, "you": true, "your": true,
+	"our": true, "have": true, "has": true, "had": true, "but": true, "not": true,
+	"all": true, "can": true, "there": true, "their": true, "they": true, "what": true,
+	"when": true, "where": true, "which": true, "into": true, "about": true, "after": true,
+	"then": true, "than": true, "some": true, "just": true, "also": true, "very": true,
+	"out": true, "one": true, "its": true, "his": true, "her": true, "who": true,
+}
+
+type RelatedHandler struct {
+	mu    sync.Mutex
+	cache map[uint]relatedEntry
+}
+
+type relatedEntry struct {
+	version uint64
+	ids     []uint
+	expires time.Time
+}
+
+func NewRelatedHandler() *RelatedHandler {
+	return &RelatedHandler{cache: make(map[uint]relatedEntry)}
+}
+
+type relatedCandidate struct {
+	ID        uint
+	Title     string
+	Excerpt   *string
+	Tags      models.StringList
+	UserID    uint
+	TripID    *uint
+	PlaceID   *uint
+	Latitude  *float64
+	Longitude *float64
+
+	terms map[string]float64
+}
+
+// relatedVersion is bumped by the post write paths of this process, so
+// results cached before a change are never served again. Other replicas
+// pick the change up once their entries expire.
+var relatedVersion atomic.Uint64
+
+// invalidateRelated discards every cached related-posts result.
+func invalidateRelated() {
+	relatedVersion.Add(1)
+}
+
+// GetRelatedPosts recommends published posts similar to the given one,
+// scored by shared tags, distance between their places, shared trip or
+// author, and overlap of the words in their titles and excerpts.
+func (h *RelatedHandler) GetRelatedPosts(c *gin.Context) {
+	id, ok := utils.ParseUintParam(c, "id", "Invalid post ID")
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "5"))
+	if err != nil || limit < 1 {
+		limit = 5
+	}
+	limit = min(limit, maxRelatedPosts)
+
+	var post models.Post
+	if err := database.DB.Select(postVisibilityColumns).First(&post, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("post not found"))
+		} else {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get post"))
+		}
+		return
+	}
+	if !checkPostVisible(c, post) {
+		return
+	}
+
+	version := relatedVersion.Load()
+	ids, cached := h.cached(post.ID, version)
+	if !cached {
+		if ids, err = h.compute(post.ID); err != nil {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get related posts"))
+			return
+		}
+		h.store(post.ID, version, ids)
+	}
+	posts := make([]models.Post, 0, len(ids))
+	if len(ids) > 0 {
+		// Cached results may predate a change on another replica, so posts
+		// that are no longer public are dropped before applying the limit.
+		var found []models.Post
+		if err := database.DB.Preload("User").Preload("Place").
+			Where("id IN ?", ids).
+			Where(relatedVisibleSQL, models.StatusPublished, models.VisibilityPublic).
+			Find(&found).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get related posts"))
+			return
+		}
+		byID := make(map[uint]models.Post, len(found))
+		for _, p := range found {
+			byID[p.ID] = p
+		}
+		for _, id := range ids {
+			if p, ok := byID[id]; ok && len(posts) < limit {
+				posts = append(posts, p)
+			}
+		}
+	}
+
+	if err := decoratePosts(c, posts); err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get related posts"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(posts))
+}
+
+func (h *RelatedHandler) cached(postID uint, version uint64) ([]uint, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	entry, ok := h.cache[postID]
+	if !ok || entry.version != version || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry.ids, true
+}
+
+func (h *RelatedHandler) store(postID uint, version uint64, ids []uint) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// Entries of an older version can never be served again.
+	if len(h.cache) >= relatedCacheSize {
+		for key, entry := range h.cache {
+			if entry.version != version || time.Now().After(entry.expires) {
+				delete(h.cache, key)
+			}
+		}
+		if len(h.cache) >= relatedCacheSize {
+			h.cache = make(map[uint]relatedEntry)
+		}
+	}
+	h.cache[postID] = relatedEntry{version: version, ids: ids, expires: time.Now().Add(relatedCacheTTL)}
+}
+
+func loadRelatedCandidates(query *gorm.DB) ([]relatedCandidate, error) {
+	var candidates []relatedCandidate
+	err := query.Model(&models.Post{}).
+		Select("posts.id, posts.title, posts.excerpt, posts.tags, posts.user_id, posts.trip_id, posts.place_id, places.latitude, places.longitude").
+		Joins("LEFT JOIN places ON places.id = posts.place_id").
+		Scan(&candidates).Error
+	for i := range candidates {
+		candidates[i].terms = termFrequencies(candidates[i].Title + " " + derefString(candidates[i].Excerpt))
+	}
+	return candidates, err
+}
+
+// compute scores the most recent public posts against postID and returns
+// the IDs of the best matches, best first. Results are shared by all readers,
+// so posts with restricted visibility are never recommended.
+func (h *RelatedHandler) compute(postID uint) ([]uint, error) {
+	sources, err := loadRelatedCandidates(database.DB.Where("posts.id = ?", postID))
+	if err != nil || len(sources) == 0 {
+		return nil, err
+	}
+	source := sources[0]
+
+	candidates, err := loadRelatedCandidates(database.DB.
+		Where(relatedVisibleSQL, models.StatusPublished, models.VisibilityPublic).
+		Where("posts.id <> ?", postID).
+		Order("posts.published_at DESC").
+		Limit(relatedCandidates))
+	if err != nil {
+		return nil, err
+	}
+
+	type scored struct {
+		id    uint
+		score float64
+	}
+	results := make([]scored, 0, len(candidates))
+	for _, candidate := range candidates {
+		score := relatedTagWeight*jaccard(source.Tags, candidate.Tags) +
+			relatedProximityWeight*proximity(source, candidate) +
+			relatedTextWeight*cosine(source.terms, candidate.terms)
+		if source.TripID != nil && candidate.TripID != nil && *source.TripID == *candidate.TripID {
+			score += relatedTripWeight
+		}
+		if source.UserID == candidate.UserID {
+			score += relatedAuthorWeight
+		}
+		if score > 0 {
+			results = append(results, scored{candidate.ID, score})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].score > results[j].score
+	})
+	if len(results) > maxRelatedPosts {
+		results = results[:maxRelatedPosts]
+	}
+
+	ids := make([]uint, len(results))
+	for i, r := range results {
+		ids[i] = r.id
+	}
+	return ids, nil
+}
+
+func jaccard(a, b models.StringList) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	set := make(map[string]bool, len(a))
+	for _, tag := range a {
+		set[tag] = true
+	}
+	shared := 0
+	for _, tag := range b {
+		if set[tag] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// proximity is 1 for posts about the same place and decays exponentially
+// with the distance between the places' coordinates.
+func proximity(a, b relatedCandidate) float64 {
+	if a.PlaceID != nil && b.PlaceID != nil && *a.PlaceID == *b.PlaceID {
+		return 1
+	}
+	if a.Latitude == nil || a.Longitude == nil || b.Latitude == nil || b.Longitude == nil {
+		return 0
+	}
+	km := gpx.Distance(
+		gpx.Point{Latitude: *a.Latitude, Longitude: *a.Longitude},
+		gpx.Point{Latitude: *b.Latitude, Longitude: *b.Longitude},
+	) / 1000
+	return math.Exp(-km / proximityScaleKm)
+}
+
+// termFrequencies splits text into lowercase words, ignoring short words
+// and common stop words.
+func termFrequencies(text string) map[string]float64 {
+	terms := make(map[string]float64)
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		if len([]rune(word)) < 3 || relatedStopWords[word] {
+			continue
+		}
+		terms[word]++
+	}
+	return terms
+}
+
+func cosine(a, b map[string]float64) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for term, weight := range a {
+		dot += weight * b[term]
+		normA += weight * weight
+	}
+	for _, weight := range b {
+		normB += weight * weight
+	}
+	return dot / math.Sqrt(normA*normB)
+}
@@ -0,0 +1,431 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+	"travel-blog-backend/internal/config"
+	"travel-blog-backend/internal/database"
+	"travel-blog-backend/internal/models"
+	"travel-blog-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReportHandler struct{}
+
+func NewReportHandler() *ReportHandler {
+	return &ReportHandler{}
+}
+
+type CreateReportRequest struct {
+	TargetType string  `json:"targetType" binding:"required"`
+	TargetID   uint    `json:"targetId" binding:"required"`
+	Reason     string  `json:"reason" binding:"required"`
+	Details    *string `json:"details" binding:"omitempty,max=2000"`
+}
+
+type ResolveReportRequest struct {
+	Action string `json:"action" binding:"required"`
+	// SuspendDays limits a suspension; without it the user stays suspended
+	// until an admin lifts it.
+	SuspendDays *int `json:"suspendDays" binding:"omitempty,min=1,max=3650"`
+}
+
+type ReportListResponse struct {
+	Reports    []models.Report `json:"reports"`
+	Total      int64           `json:"total"`
+	Page       int             `json:"page"`
+	PageSize   int             `json:"pageSize"`
+	TotalPages int             `json:"totalPages"`
+}
+
+func parseReportReason(value string) (models.ReportReason, bool) {
+	switch reason := models.ReportReason(value); reason {
+	case models.ReasonSpam, models.ReasonHarassment, models.ReasonHate, models.ReasonViolence,
+		models.ReasonSexual, models.ReasonMisinformation, models.ReasonCopyright, models.ReasonOther:
+		return reason, true
+	}
+	return "", false
+}
+
+func checkAdmin(c *gin.Context) bool {
+	userRole, _ := c.Get("userRole")
+	if userRole.(string) != "admin" {
+		c.JSON(http.StatusForbidden, utils.ErrorResponse("permission denied"))
+		return false
+	}
+	return true
+}
+
+// reportTargetOwner resolves the target of a report to the user responsible
+// for it, writing an error response if the target does not exist or the
+// current user may not see it.
+func reportTargetOwner(c *gin.Context, targetType models.ReportTargetType, targetID uint) (uint, bool) {
+	switch targetType {
+	case models.ReportPost:
+		var post models.Post
+		if err := database.DB.Select(postVisibilityColumns).First(&post, targetID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, utils.ErrorResponse("post not found"))
+			} else {
+				c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get post"))
+			}
+			return 0, false
+		}
+		if !checkPostVisible(c, post) {
+			return 0, false
+		}
+		return post.UserID, true
+	case models.ReportComment:
+		var comment models.Comment
+		if err := database.DB.Select("id", "post_id", "user_id").First(&comment, targetID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, utils.ErrorResponse("comment not found"))
+			} else {
+				c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get comment"))
+			}
+			return 0, false
+		}
+		if !checkPostIDVisible(c, comment.PostID) {
+			return 0, false
+		}
+		return comment.UserID, true
+	case models.ReportUser:
+		var user models.User
+		if err := database.DB.Select("id").First(&user, targetID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, utils.ErrorResponse("user not found"))
+			} else {
+				c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get user"))
+			}
+			return 0, false
+		}
+		return user.ID, true
+	}
+	c.JSON(http.StatusBadRequest, utils.ErrorResponse("targetType must be one of post, comment, user"))
+	return 0, false
+}
+
+// hiddenTargetModel returns the model of targets that are hidden once
+// enough people report them, or nil for targets that are never hidden.
+func hiddenTargetModel(targetType models.ReportTargetType) interface{} {
+	switch targetType {
+	case models.ReportPost:
+		return &models.Post{}
+	case models.ReportComment:
+		return &models.Comment{}
+	}
+	return nil
+}
+
+// CreateReport flags a post, comment or user. Reporting the same target
+// again while the first report is open returns that report. Posts and
+// comments are hidden once ReportHideThreshold different users have open
+// reports on them.
+func (h *ReportHandler) CreateReport(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req CreateReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request data: "+err.Error()))
+		return
+	}
+
+	reason, valid := parseReportReason(req.Reason)
+	if !valid {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("reason must be one of spam, harassment, hate, violence, sexual, misinformation, copyright, other"))
+		return
+	}
+
+	targetType := models.ReportTargetType(req.TargetType)
+	ownerID, ok := reportTargetOwner(c, targetType, req.TargetID)
+	if !ok {
+		return
+	}
+	if ownerID == userID.(uint) {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("you cannot report yourself"))
+		return
+	}
+
+	if req.Details != nil && strings.TrimSpace(*req.Details) == "" {
+		req.Details = nil
+	}
+	report := models.Report{
+		ReporterID: userID.(uint),
+		TargetType: targetType,
+		TargetID:   req.TargetID,
+		Reason:     reason,
+		Details:    req.Details,
+		Status:     models.ReportOpen,
+	}
+
+	created := false
+	model := hiddenTargetModel(targetType)
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		// Reports on a post or comment are serialized on its row, so that
+		// concurrent reports cannot each count before the others commit and
+		// all miss the threshold.
+		if model != nil {
+			if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(model, req.TargetID).Error; err != nil {
+				return err
+			}
+		}
+
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&report)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return tx.Where("reporter_id = ? AND target_type = ? AND target_id = ? AND status = ?",
+				userID, targetType, req.TargetID, models.ReportOpen).First(&report).Error
+		}
+		created = true
+
+		if model == nil {
+			return nil
+		}
+		var reporters int64
+		if err := tx.Model(&models.Report{}).
+			Where("target_type = ? AND target_id = ? AND status = ?", targetType, req.TargetID, models.ReportOpen).
+			Distinct("reporter_id").
+			Count(&reporters).Error; err != nil {
+			return err
+		}
+		if reporters < int64(config.AppConfig.ReportHideThreshold) {
+			return nil
+		}
+		return tx.Model(model).
+			Where("id = ? AND hidden_at IS NULL", req.TargetID).
+			Update("hidden_at", time.Now()).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to create report"))
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, utils.SuccessResponse(report))
+}
+
+// GetReports lists reports for admins to triage, oldest first. The status
+// (open by default), targetType and reason parameters narrow the list.
+func (h *ReportHandler) GetReports(c *gin.Context) {
+	if !checkAdmin(c) {
+		return
+	}
+
+	page, pageSize := utils.ParsePagination(c, 1, 20, 100)
+
+	query := database.DB.Model(&models.Report{}).
+		Where("status = ?", c.DefaultQuery("status", string(models.ReportOpen)))
+	if targetType := c.Query("targetType"); targetType != "" {
+		query = query.Where("target_type = ?", targetType)
+	}
+	if reason := c.Query("reason"); reason != "" {
+		query = query.Where("reason = ?", reason)
+	}
+
+	var reports []models.Report
+	var total int64
+	query.Count(&total)
+
+	offset := (page - 1) * pageSize
+	if err := query.Preload("Reporter").
+		Order("created_at ASC, id ASC").
+		Limit(pageSize).
+		Offset(offset).
+		Find(&reports).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get reports"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(ReportListResponse{
+		Reports:    reports,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: utils.CalculateTotalPages(total, pageSize),
+	}))
+}
+
+// ResolveReport settles a report, together with every other open report on
+// the same target. Dismissing unhides the target, deleting moves a post or
+// comment to the trash, and suspending locks out the user responsible for
+// the target.
+func (h *ReportHandler) ResolveReport(c *gin.Context) {
+	if !checkAdmin(c) {
+		return
+	}
+
+	id, ok := utils.ParseUintParam(c, "id", "Invalid report ID")
+	if !ok {
+		return
+	}
+
+	var req ResolveReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request data: "+err.Error()))
+		return
+	}
+
+	action := models.ReportAction(req.Action)
+	switch action {
+	case models.ActionDismiss, models.ActionDelete, models.ActionSuspend:
+	default:
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("action must be one of dismiss, delete, suspend"))
+		return
+	}
+
+	var report models.Report
+	if err := database.DB.First(&report, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("report not found"))
+		} else {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get report"))
+		}
+		return
+	}
+	if report.Status != models.ReportOpen {
+		c.JSON(http.StatusConflict, utils.ErrorResponse("report is already resolved"))
+		return
+	}
+	if action == models.ActionDelete && report.TargetType == models.ReportUser {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("reports on users cannot be resolved by deleting content"))
+		return
+	}
+
+	var ownerID uint
+	if action == models.ActionSuspend {
+		var err error
+		if ownerID, err = reportTargetUserID(report); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, utils.ErrorResponse("reported content no longer exists"))
+			} else {
+				c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to resolve report"))
+			}
+			return
+		}
+		var owner models.User
+		if err := database.DB.Select("id", "role").First(&owner, ownerID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, utils.ErrorResponse("user not found"))
+			} else {
+				c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to resolve report"))
+			}
+			return
+		}
+		if owner.Role == models.RoleAdmin {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("admins cannot be suspended"))
+			return
+		}
+	}
+
+	userID, _ := c.Get("userID")
+	now := time.Now()
+	status := models.ReportActioned
+	if action == models.ActionDismiss {
+		status = models.ReportDismissed
+	}
+
+	var resolved int64
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		model := hiddenTargetModel(report.TargetType)
+		switch action {
+		case models.ActionDismiss:
+			if model != nil {
+				if err := tx.Model(model).Where("id = ?", report.TargetID).Update("hidden_at", nil).Error; err != nil {
+					return err
+				}
+			}
+		case models.ActionDelete:
+			if err := tx.Delete(model, report.TargetID).Error; err != nil {
+				return err
+			}
+		case models.ActionSuspend:
+			var until *time.Time
+			if req.SuspendDays != nil {
+				end := now.AddDate(0, 0, *req.SuspendDays)
+				until = &end
+			}
+			if err := tx.Model(&models.User{}).Where("id = ?", ownerID).Updates(map[string]interface{}{
+				"suspended_at":    now,
+				"suspended_until": until,
+			}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("user_id = ?", ownerID).Delete(&models.RefreshToken{}).Error; err != nil {
+				return err
+			}
+		}
+
+		result := tx.Model(&models.Report{}).
+			Where("target_type = ? AND target_id = ? AND status = ?", report.TargetType, report.TargetID, models.ReportOpen).
+			Updates(map[string]interface{}{
+				"status":      status,
+				"action":      action,
+				"resolved_by": userID.(uint),
+				"resolved_at": now,
+			})
+		resolved = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to resolve report"))
+		return
+	}
+	if report.TargetType == models.ReportPost {
+		invalidateRelated()
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(gin.H{"resolved": resolved}))
+}
+
+// reportTargetUserID returns the user responsible for the target of report,
+// including posts and comments already in the trash.
+func reportTargetUserID(report models.Report) (uint, error) {
+	switch report.TargetType {
+	case models.ReportPost:
+		var post models.Post
+		err := database.DB.Unscoped().Select("id", "user_id").First(&post, report.TargetID).Error
+		return post.UserID, err
+	case models.ReportComment:
+		var comment models.Comment
+		err := database.DB.Unscoped().Select("id", "user_id").First(&comment, report.TargetID).Error
+		return comment.UserID, err
+	}
+	return report.TargetID, nil
+}
+
+// LiftSuspension lets a suspended user back in.
+func (h *ReportHandler) LiftSuspension(c *gin.Context) {
+	if !checkAdmin(c) {
+		return
+	}
+
+	id, ok := utils.ParseUintParam(c, "id", "Invalid user ID")
+	if !ok {
+		return
+	}
+
+	result := database.DB.Model(&models.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"suspended_at":    nil,
+		"suspended_until": nil,
+	})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to lift suspension"))
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("user not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.MessageResponse("Suspension lifted successfully"))
+}
@@ -0,0 +1,286 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"travel-blog-backend/internal/database"
+	"travel-blog-backend/internal/models"
+	"travel-blog-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RevisionHandler struct{}
+
+func NewRevisionHandler() *RevisionHandler {
+	return &RevisionHandler{}
+}
+
+type RevisionListResponse struct {
+	Revisions  []models.PostRevision `json:"revisions"`
+	Total      int64                 `json:"total"`
+	Page       int                   `json:"page"`
+	PageSize   int                   `json:"pageSize"`
+	TotalPages int                   `json:"totalPages"`
+}
+
+type RevisionDiffResponse struct {
+	From  int               `json:"from"`
+	To    int               `json:"to"`
+	Diffs map[string]string `json:"diffs"`
+}
+
+// changedRevisionFields compares the tracked fields of post with the proposed
+// values and returns the names of the ones that differ. Nil proposals mean
+// the field is not being changed.
+func changedRevisionFields(post models.Post, title, content, excerpt, imageURL *string) []string {
+	changed := make([]string, 0, 4)
+	if title != nil && *title != post.Title {
+		changed = append(changed, "title")
+	}
+	if content != nil && *content != post.Content {
+		changed = append(changed, "content")
+	}
+	if excerpt != nil && *excerpt != derefString(post.Excerpt) {
+		changed = append(changed, "excerpt")
+	}
+	if imageURL != nil && *imageURL != derefString(post.ImageURL) {
+		changed = append(changed, "imageUrl")
+	}
+	return changed
+}
+
+// recordRevision stores a snapshot of post as its next revision. Callers must
+// hold a lock on the post row so revision numbers are assigned in order.
+func recordRevision(tx *gorm.DB, post models.Post, userID uint, changed []string, restoredFrom *int) error {
+	var last int
+	if err := tx.Model(&models.PostRevision{}).
+		Where("post_id = ?", post.ID).
+		Select("COALESCE(MAX(number), 0)").
+		Scan(&last).Error; err != nil {
+		return err
+	}
+
+	revision := models.PostRevision{
+		PostID:        post.ID,
+		Number:        last + 1,
+		UserID:        userID,
+		Title:         post.Title,
+		Content:       post.Content,
+		Excerpt:       post.Excerpt,
+		ImageURL:      post.ImageURL,
+		ChangedFields: changed,
+		RestoredFrom:  restoredFrom,
+	}
+	return tx.Create(&revision).Error
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func (h *RevisionHandler) loadPost(c *gin.Context) (models.Post, bool) {
+	var post models.Post
+
+	id, ok := utils.ParseUintParam(c, "id", "Invalid post ID")
+	if !ok {
+		return post, false
+	}
+
+	if err := database.DB.First(&post, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("post not found"))
+		} else {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get post"))
+		}
+		return post, false
+	}
+
+	if !checkPostPermission(c, post) {
+		return post, false
+	}
+	return post, true
+}
+
+func (h *RevisionHandler) findRevision(c *gin.Context, postID uint, number int, revision *models.PostRevision) bool {
+	if err := database.DB.Preload("User").
+		Where("post_id = ? AND number = ?", postID, number).
+		First(revision).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("revision not found"))
+		} else {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get revision"))
+		}
+		return false
+	}
+	return true
+}
+
+func parseRevisionNumber(c *gin.Context, raw string) (int, bool) {
+	number, err := strconv.Atoi(raw)
+	if err != nil || number < 1 {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid revision number"))
+		return 0, false
+	}
+	return number, true
+}
+
+func (h *RevisionHandler) GetRevisions(c *gin.Context) {
+	post, ok := h.loadPost(c)
+	if !ok {
+		return
+	}
+
+	page, pageSize := utils.ParsePagination(c, 1, 20, 100)
+
+	var revisions []models.PostRevision
+	var total int64
+
+	query := database.DB.Model(&models.PostRevision{}).Where("post_id = ?", post.ID)
+	query.Count(&total)
+
+	offset := (page - 1) * pageSize
+	if err := query.Preload("User").
+		Omit("content").
+		Order("number DESC").
+		Limit(pageSize).
+		Offset(offset).
+		Find(&revisions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get revisions"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(RevisionListResponse{
+		Revisions:  revisions,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: utils.CalculateTotalPages(total, pageSize),
+	}))
+}
+
+func (h *RevisionHandler) GetRevision(c *gin.Context) {
+	post, ok := h.loadPost(c)
+	if !ok {
+		return
+	}
+
+	number, ok := parseRevisionNumber(c, c.Param("number"))
+	if !ok {
+		return
+	}
+
+	var revision models.PostRevision
+	if !h.findRevision(c, post.ID, number, &revision) {
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(revision))
+}
+
+func (h *RevisionHandler) DiffRevisions(c *gin.Context) {
+	post, ok := h.loadPost(c)
+	if !ok {
+		return
+	}
+
+	from, ok := parseRevisionNumber(c, c.Query("from"))
+	if !ok {
+		return
+	}
+	to, ok := parseRevisionNumber(c, c.Query("to"))
+	if !ok {
+		return
+	}
+
+	var fromRevision, toRevision models.PostRevision
+	if !h.findRevision(c, post.ID, from, &fromRevision) || !h.findRevision(c, post.ID, to, &toRevision) {
+		return
+	}
+
+	fields := []struct {
+		name     string
+		from, to string
+	}{
+		{"title", fromRevision.Title, toRevision.Title},
+		{"content", fromRevision.Content, toRevision.Content},
+		{"excerpt", derefString(fromRevision.Excerpt), derefString(toRevision.Excerpt)},
+		{"imageUrl", derefString(fromRevision.ImageURL), derefString(toRevision.ImageURL)},
+	}
+
+	diffs := make(map[string]string)
+	for _, field := range fields {
+		diff := utils.UnifiedDiff(
+			fmt.Sprintf("%s@%d", field.name, from),
+			fmt.Sprintf("%s@%d", field.name, to),
+			field.from, field.to, 3,
+		)
+		if diff != "" {
+			diffs[field.name] = diff
+		}
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(RevisionDiffResponse{
+		From:  from,
+		To:    to,
+		Diffs: diffs,
+	}))
+}
+
+func (h *RevisionHandler) RestoreRevision(c *gin.Context) {
+	post, ok := h.loadPost(c)
+	if !ok {
+		return
+	}
+
+	number, ok := parseRevisionNumber(c, c.Param("number"))
+	if !ok {
+		return
+	}
+
+	var revision models.PostRevision
+	if !h.findRevision(c, post.ID, number, &revision) {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&post, post.ID).Error; err != nil {
+			return err
+		}
+
+		excerpt, imageURL := derefString(revision.Excerpt), derefString(revision.ImageURL)
+		changed := changedRevisionFields(post, &revision.Title, &revision.Content, &excerpt, &imageURL)
+		if len(changed) == 0 {
+			return nil
+		}
+
+		if err := tx.Model(&post).Updates(map[string]interface{}{
+			"title":     revision.Title,
+			"content":   revision.Content,
+			"excerpt":   revision.Excerpt,
+			"image_url": revision.ImageURL,
+		}).Error; err != nil {
+			return err
+		}
+		if err := rerenderPost(tx, &post); err != nil {
+			return err
+		}
+		return recordRevision(tx, post, userID.(uint), changed, &revision.Number)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to restore revision"))
+		return
+	}
+	invalidateRelated()
+
+	database.DB.Preload("User").Preload("Place").First(&post, post.ID)
+	c.JSON(http.StatusOK, utils.SuccessResponse(post))
+}
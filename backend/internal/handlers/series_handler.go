@@ -0,0 +1,421 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"travel-blog-backend/internal/database"
+	"travel-blog-backend/internal/models"
+	"travel-blog-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SeriesHandler struct{}
+
+func NewSeriesHandler() *SeriesHandler {
+	return &SeriesHandler{}
+}
+
+type CreateSeriesRequest struct {
+	Title       string  `json:"title" binding:"required"`
+	Description *string `json:"description"`
+}
+
+type UpdateSeriesRequest struct {
+	Title       *string `json:"title"`
+	Description *string `json:"description"`
+}
+
+type AddSeriesPostRequest struct {
+	PostID uint `json:"postId" binding:"required"`
+}
+
+type ReorderSeriesRequest struct {
+	PostIDs []uint `json:"postIds" binding:"required"`
+}
+
+type SeriesListResponse struct {
+	Series     []models.Series `json:"series"`
+	Total      int64           `json:"total"`
+	Page       int             `json:"page"`
+	PageSize   int             `json:"pageSize"`
+	TotalPages int             `json:"totalPages"`
+}
+
+// publicPartsCountSQL counts the parts of a series anyone can read.
+const publicPartsCountSQL = `(SELECT COUNT(*) FROM posts WHERE posts.series_id = series.id
+	AND posts.status = 'published' AND posts.visibility = 'public' AND posts.hidden_at IS NULL AND posts.deleted_at IS NULL) AS parts_count`
+
+func checkSeriesPermission(c *gin.Context, series models.Series) bool {
+	userID, _ := c.Get("userID")
+	userRole, _ := c.Get("userRole")
+	if series.UserID != userID.(uint) && userRole.(string) != "admin" {
+		c.JSON(http.StatusForbidden, utils.ErrorResponse("permission denied"))
+		return false
+	}
+	return true
+}
+
+func (h *SeriesHandler) findSeries(c *gin.Context, series *models.Series) bool {
+	id, ok := utils.ParseUintParam(c, "id", "Invalid series ID")
+	if !ok {
+		return false
+	}
+
+	if err := database.DB.First(series, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("series not found"))
+		} else {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get series"))
+		}
+		return false
+	}
+	return true
+}
+
+// seriesParts selects the parts of a series the current reader can see, in
+// order: published parts they may read and, for authors, their own parts in
+// any status.
+func seriesParts(c *gin.Context, seriesID uint) *gorm.DB {
+	visible := visiblePostsScope(c, false)(database.DB.Where("posts.status = ?", models.StatusPublished))
+	if userID, exists := c.Get("userID"); exists {
+		visible = visible.Or(authoredPostsSQL, userID.(uint))
+	}
+	return database.DB.Model(&models.Post{}).
+		Where("posts.series_id = ?", seriesID).
+		Where(visible).
+		Order("posts.series_position ASC, posts.id ASC")
+}
+
+// attachSeriesNavigation fills in where post sits in its series, with links
+// to the neighbouring parts.
+func attachSeriesNavigation(c *gin.Context, post *models.Post) error {
+	if post.SeriesID == nil {
+		return nil
+	}
+
+	var series models.Series
+	if err := database.DB.Select("id", "title").First(&series, *post.SeriesID).Error; err != nil {
+		return err
+	}
+
+	var parts []models.SeriesPart
+	if err := seriesParts(c, series.ID).Select("posts.id", "posts.title").Scan(&parts).Error; err != nil {
+		return err
+	}
+
+	for i, part := range parts {
+		if part.ID != post.ID {
+			continue
+		}
+		navigation := &models.SeriesNavigation{
+			ID:    series.ID,
+			Title: series.Title,
+			Part:  i + 1,
+			Parts: len(parts),
+		}
+		if i > 0 {
+			navigation.Previous = &parts[i-1]
+		}
+		if i < len(parts)-1 {
+			navigation.Next = &parts[i+1]
+		}
+		post.Navigation = navigation
+		break
+	}
+	return nil
+}
+
+// GetSeriesByUser lists the series created by a user, newest first.
+func (h *SeriesHandler) GetSeriesByUser(c *gin.Context) {
+	userID, ok := utils.ParseUintParam(c, "id", "Invalid user ID")
+	if !ok {
+		return
+	}
+
+	page, pageSize := utils.ParsePagination(c, 1, 10, 100)
+
+	var series []models.Series
+	var total int64
+
+	query := database.DB.Model(&models.Series{}).Where("user_id = ?", userID)
+	query.Count(&total)
+
+	offset := (page - 1) * pageSize
+	if err := query.Preload("User").
+		Select("series.*, " + publicPartsCountSQL).
+		Order("created_at DESC, id DESC").
+		Limit(pageSize).
+		Offset(offset).
+		Find(&series).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get series"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(SeriesListResponse{
+		Series:     series,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: utils.CalculateTotalPages(total, pageSize),
+	}))
+}
+
+// GetSeries returns a series with the parts the reader can see, in order.
+func (h *SeriesHandler) GetSeries(c *gin.Context) {
+	var series models.Series
+	if !h.findSeries(c, &series) {
+		return
+	}
+	h.respond(c, series, http.StatusOK)
+}
+
+func (h *SeriesHandler) respond(c *gin.Context, series models.Series, status int) {
+	if err := database.DB.Preload("User").First(&series, series.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get series"))
+		return
+	}
+
+	parts := make([]models.Post, 0)
+	if err := seriesParts(c, series.ID).Preload("User").Find(&parts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get series"))
+		return
+	}
+	if err := decoratePosts(c, parts); err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get series"))
+		return
+	}
+	series.Parts = parts
+	series.PartsCount = int64(len(parts))
+
+	c.JSON(status, utils.SuccessResponse(series))
+}
+
+func (h *SeriesHandler) CreateSeries(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req CreateSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request data: "+err.Error()))
+		return
+	}
+
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("title must not be empty"))
+		return
+	}
+
+	series := models.Series{
+		Title:       title,
+		Description: req.Description,
+		UserID:      userID.(uint),
+	}
+	if err := database.DB.Create(&series).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to create series"))
+		return
+	}
+
+	h.respond(c, series, http.StatusCreated)
+}
+
+func (h *SeriesHandler) UpdateSeries(c *gin.Context) {
+	var req UpdateSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request data: "+err.Error()))
+		return
+	}
+
+	var series models.Series
+	if !h.findSeries(c, &series) || !checkSeriesPermission(c, series) {
+		return
+	}
+
+	updates := make(map[string]interface{})
+	if req.Title != nil {
+		title := strings.TrimSpace(*req.Title)
+		if title == "" {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("title must not be empty"))
+			return
+		}
+		updates["title"] = title
+	}
+	if req.Description != nil {
+		updates["description"] = req.Description
+	}
+
+	if err := database.DB.Model(&series).Updates(updates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to update series"))
+		return
+	}
+
+	h.respond(c, series, http.StatusOK)
+}
+
+// DeleteSeries removes a series. Its posts are kept as standalone posts.
+func (h *SeriesHandler) DeleteSeries(c *gin.Context) {
+	var series models.Series
+	if !h.findSeries(c, &series) || !checkSeriesPermission(c, series) {
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&models.Post{}).
+			Where("series_id = ?", series.ID).
+			UpdateColumns(map[string]interface{}{"series_id": nil, "series_position": nil}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&series).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to delete series"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.MessageResponse("Series deleted successfully"))
+}
+
+// AddPost appends a post to the series as its last part, moving it out of
+// any other series. The current user must be allowed to edit both.
+func (h *SeriesHandler) AddPost(c *gin.Context) {
+	var req AddSeriesPostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request data: "+err.Error()))
+		return
+	}
+
+	var series models.Series
+	if !h.findSeries(c, &series) || !checkSeriesPermission(c, series) {
+		return
+	}
+
+	var post models.Post
+	if err := database.DB.Select("id", "user_id", "series_id").First(&post, req.PostID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("post not found"))
+		} else {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get post"))
+		}
+		return
+	}
+	if !checkPostPermission(c, post) {
+		return
+	}
+	if post.SeriesID != nil && *post.SeriesID == series.ID {
+		c.JSON(http.StatusConflict, utils.ErrorResponse("post is already part of this series"))
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&models.Series{}, series.ID).Error; err != nil {
+			return err
+		}
+		var last int
+		if err := tx.Unscoped().Model(&models.Post{}).
+			Where("series_id = ?", series.ID).
+			Select("COALESCE(MAX(series_position), 0)").
+			Scan(&last).Error; err != nil {
+			return err
+		}
+		return tx.Model(&post).UpdateColumns(map[string]interface{}{
+			"series_id":       series.ID,
+			"series_position": last + 1,
+		}).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to add post to series"))
+		return
+	}
+
+	h.respond(c, series, http.StatusOK)
+}
+
+// RemovePost takes a post out of the series. The remaining parts keep their
+// order.
+func (h *SeriesHandler) RemovePost(c *gin.Context) {
+	var series models.Series
+	if !h.findSeries(c, &series) || !checkSeriesPermission(c, series) {
+		return
+	}
+
+	postID, ok := utils.ParseUintParam(c, "postId", "Invalid post ID")
+	if !ok {
+		return
+	}
+
+	result := database.DB.Unscoped().Model(&models.Post{}).
+		Where("id = ? AND series_id = ?", postID, series.ID).
+		UpdateColumns(map[string]interface{}{"series_id": nil, "series_position": nil})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to remove post from series"))
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("post is not part of this series"))
+		return
+	}
+
+	h.respond(c, series, http.StatusOK)
+}
+
+// ReorderPosts sets the order of the parts. postIds must list every post in
+// the series exactly once; posts in the trash are not listed.
+func (h *SeriesHandler) ReorderPosts(c *gin.Context) {
+	var req ReorderSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request data: "+err.Error()))
+		return
+	}
+
+	var series models.Series
+	if !h.findSeries(c, &series) || !checkSeriesPermission(c, series) {
+		return
+	}
+
+	errMismatch := errors.New("postIds must list every post in the series exactly once")
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&models.Series{}, series.ID).Error; err != nil {
+			return err
+		}
+
+		var ids []uint
+		if err := tx.Model(&models.Post{}).Where("series_id = ?", series.ID).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		existing := make(map[uint]bool, len(ids))
+		for _, id := range ids {
+			existing[id] = true
+		}
+		if len(req.PostIDs) != len(ids) {
+			return errMismatch
+		}
+		for _, id := range req.PostIDs {
+			if !existing[id] {
+				return errMismatch
+			}
+			delete(existing, id)
+		}
+
+		for i, id := range req.PostIDs {
+			if err := tx.Model(&models.Post{}).
+				Where("id = ?", id).
+				UpdateColumn("series_position", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, errMismatch) {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to reorder series"))
+		}
+		return
+	}
+
+	h.respond(c, series, http.StatusOK)
+}
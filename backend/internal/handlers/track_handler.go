@@ -0,0 +1,272 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"travel-blog-backend/internal/config"
+	"travel-blog-backend/internal/database"
+	"travel-blog-backend/internal/gpx"
+	"travel-blog-backend/internal/models"
+	"travel-blog-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// trackTolerance is the Douglas-Peucker tolerance in meters used for the
+// display copy of a track.
+const trackTolerance = 10.0
+
+type TrackHandler struct{}
+
+func NewTrackHandler() *TrackHandler {
+	return &TrackHandler{}
+}
+
+type GeoJSONGeometry struct {
+	Type        string        `json:"type"`
+	Coordinates [][][]float64 `json:"coordinates"`
+}
+
+type GeoJSONFeature struct {
+	Type       string          `json:"type"`
+	BBox       []float64       `json:"bbox"`
+	Geometry   GeoJSONGeometry `json:"geometry"`
+	Properties models.Track    `json:"properties"`
+}
+
+func (h *TrackHandler) findTrack(c *gin.Context, track *models.Track, withPoints bool) bool {
+	id, ok := utils.ParseUintParam(c, "id", "Invalid track ID")
+	if !ok {
+		return false
+	}
+
+	query := database.DB
+	if !withPoints {
+		query = query.Omit("points", "simplified_points")
+	}
+	if err := query.First(track, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("track not found"))
+		} else {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get track"))
+		}
+		return false
+	}
+	return true
+}
+
+func (h *TrackHandler) GetPostTracks(c *gin.Context) {
+	if owner, ok := postAttachmentOwner(c, false); ok {
+		h.list(c, owner)
+	}
+}
+
+func (h *TrackHandler) UploadPostTrack(c *gin.Context) {
+	if owner, ok := postAttachmentOwner(c, true); ok {
+		h.upload(c, owner)
+	}
+}
+
+func (h *TrackHandler) GetTripTracks(c *gin.Context) {
+	if owner, ok := tripAttachmentOwner(c, false); ok {
+		h.list(c, owner)
+	}
+}
+
+func (h *TrackHandler) UploadTripTrack(c *gin.Context) {
+	if owner, ok := tripAttachmentOwner(c, true); ok {
+		h.upload(c, owner)
+	}
+}
+
+func (h *TrackHandler) list(c *gin.Context, owner attachmentOwner) {
+	tracks := make([]models.Track, 0)
+	if err := owner.scope(database.DB).
+		Omit("points", "simplified_points").
+		Order("COALESCE(started_at, created_at) ASC, id ASC").
+		Find(&tracks).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get tracks"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(tracks))
+}
+
+func (h *TrackHandler) upload(c *gin.Context, owner attachmentOwner) {
+	userID, _ := c.Get("userID")
+
+	maxBytes := int64(config.AppConfig.MaxUploadMB) << 20
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes+1<<20)
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, utils.ErrorResponse(fmt.Sprintf("file must not exceed %d MB", config.AppConfig.MaxUploadMB)))
+			return
+		}
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("file is required"))
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxBytes+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("failed to read file"))
+		return
+	}
+	if int64(len(data)) > maxBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, utils.ErrorResponse(fmt.Sprintf("file must not exceed %d MB", config.AppConfig.MaxUploadMB)))
+		return
+	}
+
+	parsed, err := gpx.Parse(bytes.NewReader(data))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+
+	name := strings.TrimSpace(c.PostForm("name"))
+	if name == "" {
+		name = parsed.Name
+	}
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(header.Filename), filepath.Ext(header.Filename))
+	}
+
+	stats := parsed.Stats()
+	simplified := make([][]gpx.Point, len(parsed.Segments))
+	for i, segment := range parsed.Segments {
+		simplified[i] = gpx.Simplify(segment, trackTolerance)
+	}
+
+	track := models.Track{
+		UserID:           userID.(uint),
+		Name:             name,
+		Distance:         stats.Distance,
+		ElevationGain:    stats.ElevationGain,
+		Duration:         int64(stats.Duration.Seconds()),
+		StartedAt:        stats.StartedAt,
+		EndedAt:          stats.EndedAt,
+		MinLatitude:      stats.Bounds.MinLatitude,
+		MinLongitude:     stats.Bounds.MinLongitude,
+		MaxLatitude:      stats.Bounds.MaxLatitude,
+		MaxLongitude:     stats.Bounds.MaxLongitude,
+		PointCount:       stats.PointCount,
+		Points:           trackPoints(parsed.Segments),
+		SimplifiedPoints: trackPoints(simplified),
+	}
+	track.PostID, track.TripID = owner.ids()
+
+	if err := database.DB.Create(&track).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to save track"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.SuccessResponse(track))
+}
+
+func trackPoints(segments [][]gpx.Point) models.TrackPoints {
+	var result models.TrackPoints
+	for i, segment := range segments {
+		for _, p := range segment {
+			result = append(result, models.TrackPoint{
+				Latitude:  p.Latitude,
+				Longitude: p.Longitude,
+				Elevation: p.Elevation,
+				Time:      p.Time,
+				Segment:   i,
+			})
+		}
+	}
+	return result
+}
+
+func (h *TrackHandler) GetTrack(c *gin.Context) {
+	var track models.Track
+	if !h.findTrack(c, &track, false) {
+		return
+	}
+	if track.PostID != nil && !checkPostIDVisible(c, *track.PostID) {
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(track))
+}
+
+// GetTrackGeoJSON serves the track as a GeoJSON MultiLineString feature with
+// one line per GPX segment. The simplified copy is returned unless
+// detail=full is requested.
+func (h *TrackHandler) GetTrackGeoJSON(c *gin.Context) {
+	var track models.Track
+	if !h.findTrack(c, &track, true) {
+		return
+	}
+	if track.PostID != nil && !checkPostIDVisible(c, *track.PostID) {
+		return
+	}
+
+	points := track.SimplifiedPoints
+	if c.Query("detail") == "full" {
+		points = track.Points
+	}
+
+	withElevation := len(points) > 0
+	for _, p := range points {
+		if p.Elevation == nil {
+			withElevation = false
+			break
+		}
+	}
+
+	var lines [][][]float64
+	for i, p := range points {
+		if i == 0 || p.Segment != points[i-1].Segment {
+			lines = append(lines, nil)
+		}
+		position := []float64{p.Longitude, p.Latitude}
+		if withElevation {
+			position = append(position, *p.Elevation)
+		}
+		lines[len(lines)-1] = append(lines[len(lines)-1], position)
+	}
+
+	// A line needs at least two positions to be valid GeoJSON.
+	coordinates := make([][][]float64, 0, len(lines))
+	for _, line := range lines {
+		if len(line) >= 2 {
+			coordinates = append(coordinates, line)
+		}
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(GeoJSONFeature{
+		Type:       "Feature",
+		BBox:       []float64{track.MinLongitude, track.MinLatitude, track.MaxLongitude, track.MaxLatitude},
+		Geometry:   GeoJSONGeometry{Type: "MultiLineString", Coordinates: coordinates},
+		Properties: track,
+	}))
+}
+
+func (h *TrackHandler) DeleteTrack(c *gin.Context) {
+	var track models.Track
+	if !h.findTrack(c, &track, false) {
+		return
+	}
+
+	if _, ok := attachmentOwnerOf(c, track.PostID, track.TripID); !ok {
+		return
+	}
+
+	if err := database.DB.Delete(&track).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to delete track"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.MessageResponse("Track deleted successfully"))
+}
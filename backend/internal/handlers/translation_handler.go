@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"travel-blog-backend/internal/content"
+	"travel-blog-backend/internal/database"
+	"travel-blog-backend/internal/models"
+	"travel-blog-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// defaultPostLanguage is the language of posts created without one.
+const defaultPostLanguage = "en"
+
+type TranslationHandler struct{}
+
+func NewTranslationHandler() *TranslationHandler {
+	return &TranslationHandler{}
+}
+
+type TranslationRequest struct {
+	Title         string  `json:"title" binding:"required"`
+	Content       string  `json:"content" binding:"required"`
+	ContentFormat string  `json:"contentFormat"`
+	Excerpt       *string `json:"excerpt"`
+}
+
+// parsePostLanguage normalizes the language of a post, defaulting to
+// defaultPostLanguage.
+func parsePostLanguage(value string) (string, bool) {
+	if value == "" {
+		return defaultPostLanguage, true
+	}
+	return utils.NormalizeLanguage(value)
+}
+
+// preferredLanguages returns the languages the reader asked for, most
+// preferred first: the lang query parameter if it is valid, otherwise the
+// Accept-Language header.
+func preferredLanguages(c *gin.Context) []string {
+	if value := c.Query("lang"); value != "" {
+		if lang, ok := utils.NormalizeLanguage(value); ok {
+			return []string{lang}
+		}
+	}
+	return utils.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+}
+
+// applyTranslations lists the languages every post is available in and
+// swaps in the translation that best matches the reader's preferences.
+// Posts without a matching translation keep their original language.
+func applyTranslations(c *gin.Context, posts []models.Post) error {
+	c.Header("Vary", "Accept-Language")
+	if len(posts) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(posts))
+	index := make(map[uint]int, len(posts))
+	for i := range posts {
+		ids[i] = posts[i].ID
+		index[posts[i].ID] = i
+		posts[i].ContentLanguage = posts[i].Language
+		posts[i].Languages = []string{posts[i].Language}
+	}
+
+	var available []struct {
+		PostID   uint
+		Language string
+	}
+	if err := database.DB.Model(&models.PostTranslation{}).
+		Select("post_id, language").
+		Where("post_id IN ?", ids).
+		Order("language ASC").
+		Scan(&available).Error; err != nil {
+		return err
+	}
+	for _, row := range available {
+		i := index[row.PostID]
+		posts[i].Languages = append(posts[i].Languages, row.Language)
+	}
+
+	preferred := preferredLanguages(c)
+	if len(preferred) == 0 {
+		return nil
+	}
+
+	var wanted [][]interface{}
+	for i := range posts {
+		match := utils.MatchLanguage(preferred, posts[i].Languages)
+		if match != "" && match != posts[i].Language {
+			wanted = append(wanted, []interface{}{posts[i].ID, match})
+		}
+	}
+	if len(wanted) == 0 {
+		return nil
+	}
+
+	var translations []models.PostTranslation
+	if err := database.DB.Where("(post_id, language) IN ?", wanted).Find(&translations).Error; err != nil {
+		return err
+	}
+	for _, translation := range translations {
+		post := &posts[index[translation.PostID]]
+		post.Title = translation.Title
+		post.Content = translation.Content
+		post.ContentFormat = translation.ContentFormat
+		post.ContentHTML = translation.ContentHTML
+		post.TOC = translation.TOC
+		post.ReadingTime = translation.ReadingTime
+		post.Excerpt = translation.Excerpt
+		post.ContentLanguage = translation.Language
+	}
+	return nil
+}
+
+func (h *TranslationHandler) findPost(c *gin.Context, post *models.Post) bool {
+	id, ok := utils.ParseUintParam(c, "id", "Invalid post ID")
+	if !ok {
+		return false
+	}
+
+	if err := database.DB.Select(append(postVisibilityColumns, "language")).First(post, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("post not found"))
+		} else {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get post"))
+		}
+		return false
+	}
+	return true
+}
+
+// translationLanguage parses the :lang parameter of a translation of post.
+func (h *TranslationHandler) translationLanguage(c *gin.Context, post models.Post) (string, bool) {
+	lang, ok := utils.NormalizeLanguage(c.Param("lang"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("invalid language"))
+		return "", false
+	}
+	if lang == post.Language {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("translation language must differ from the post's language"))
+		return "", false
+	}
+	return lang, true
+}
+
+// GetTranslations lists the translations of a post by language.
+func (h *TranslationHandler) GetTranslations(c *gin.Context) {
+	var post models.Post
+	if !h.findPost(c, &post) || !checkPostVisible(c, post) {
+		return
+	}
+
+	translations := make([]models.PostTranslation, 0)
+	if err := database.DB.Where("post_id = ?", post.ID).
+		Order("language ASC").
+		Find(&translations).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get translations"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(translations))
+}
+
+// PutTranslation creates or replaces the translation of a post into the
+// :lang language.
+func (h *TranslationHandler) PutTranslation(c *gin.Context) {
+	var req TranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request data: "+err.Error()))
+		return
+	}
+
+	format, valid := content.ParseFormat(req.ContentFormat)
+	if !valid {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("invalid contentFormat"))
+		return
+	}
+
+	var post models.Post
+	if !h.findPost(c, &post) || !checkPostPermission(c, post) {
+		return
+	}
+	lang, ok := h.translationLanguage(c, post)
+	if !ok {
+		return
+	}
+
+	var translation models.PostTranslation
+	created := false
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&models.Post{}, post.ID).Error; err != nil {
+			return err
+		}
+
+		var existing []models.PostTranslation
+		if err := tx.Where("post_id = ? AND language = ?", post.ID, lang).Limit(1).Find(&existing).Error; err != nil {
+			return err
+		}
+		if len(existing) > 0 {
+			translation = existing[0]
+		} else {
+			translation = models.PostTranslation{PostID: post.ID, Language: lang}
+			created = true
+		}
+
+		translation.Title = req.Title
+		translation.Content = req.Content
+		translation.ContentFormat = format
+		translation.Excerpt = req.Excerpt
+		translation.ExcerptGenerated = false
+		if err := content.ApplyToTranslation(&translation); err != nil {
+			return err
+		}
+		return tx.Save(&translation).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to save translation"))
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, utils.SuccessResponse(translation))
+}
+
+func (h *TranslationHandler) DeleteTranslation(c *gin.Context) {
+	var post models.Post
+	if !h.findPost(c, &post) || !checkPostPermission(c, post) {
+		return
+	}
+	lang, ok := h.translationLanguage(c, post)
+	if !ok {
+		return
+	}
+
+	result := database.DB.Where("post_id = ? AND language = ?", post.ID, lang).Delete(&models.PostTranslation{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to delete translation"))
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("translation not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.MessageResponse("Translation deleted successfully"))
+}
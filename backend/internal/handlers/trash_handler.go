@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"travel-blog-backend/internal/database"
+	"travel-blog-backend/internal/models"
+	"travel-blog-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type TrashHandler struct{}
+
+func NewTrashHandler() *TrashHandler {
+	return &TrashHandler{}
+}
+
+// trashScope limits a query to soft-deleted rows owned by the current user,
+// as matched by ownedSQL with the user's ID as its argument. Admins may pass
+// all=true to see every user's trash.
+func trashScope(c *gin.Context, query *gorm.DB, ownedSQL string) *gorm.DB {
+	userID, _ := c.Get("userID")
+	userRole, _ := c.Get("userRole")
+
+	query = query.Unscoped().Where("deleted_at IS NOT NULL")
+	if userRole.(string) == "admin" && c.Query("all") == "true" {
+		return query
+	}
+	return query.Where(ownedSQL, userID.(uint))
+}
+
+func checkCommentPermission(c *gin.Context, comment models.Comment) bool {
+	userID, _ := c.Get("userID")
+	userRole, _ := c.Get("userRole")
+	if comment.UserID != userID.(uint) && userRole.(string) != "admin" {
+		c.JSON(http.StatusForbidden, utils.ErrorResponse("permission denied"))
+		return false
+	}
+	return true
+}
+
+func (h *TrashHandler) findDeleted(c *gin.Context, model interface{}, id uint, name string) bool {
+	if err := database.DB.Unscoped().Where("deleted_at IS NOT NULL").First(model, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse(name+" not found in trash"))
+		} else {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get "+name))
+		}
+		return false
+	}
+	return true
+}
+
+func (h *TrashHandler) GetTrashedPosts(c *gin.Context) {
+	page, pageSize := utils.ParsePagination(c, 1, 10, 100)
+
+	var posts []models.Post
+	var total int64
+
+	// Every owner of a post may restore it, not just its creator.
+	query := trashScope(c, database.DB.Model(&models.Post{}), ownedPostsSQL)
+	query.Count(&total)
+
+	offset := (page - 1) * pageSize
+	if err := query.Preload("User").
+		Order("deleted_at DESC").
+		Limit(pageSize).
+		Offset(offset).
+		Find(&posts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get trash"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(PostListResponse{
+		Posts:      posts,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: utils.CalculateTotalPages(total, pageSize),
+	}))
+}
+
+func (h *TrashHandler) RestorePost(c *gin.Context) {
+	id, ok := utils.ParseUintParam(c, "id", "Invalid post ID")
+	if !ok {
+		return
+	}
+
+	var post models.Post
+	if !h.findDeleted(c, &post, id, "post") {
+		return
+	}
+
+	if !checkPostOwnership(c, post) {
+		return
+	}
+
+	if err := database.DB.Unscoped().Model(&post).Update("deleted_at", nil).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to restore post"))
+		return
+	}
+	invalidateRelated()
+
+	database.DB.Preload("User").Preload("Place").First(&post, post.ID)
+	c.JSON(http.StatusOK, utils.SuccessResponse(post))
+}
+
+func (h *TrashHandler) PurgePost(c *gin.Context) {
+	id, ok := utils.ParseUintParam(c, "id", "Invalid post ID")
+	if !ok {
+		return
+	}
+
+	var post models.Post
+	if !h.findDeleted(c, &post, id, "post") {
+		return
+	}
+
+	if !checkPostOwnership(c, post) {
+		return
+	}
+
+	if err := database.DB.Unscoped().Delete(&post).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to purge post"))
+		return
+	}
+	invalidateRelated()
+
+	c.JSON(http.StatusOK, utils.MessageResponse("Post permanently deleted"))
+}
+
+func (h *TrashHandler) GetTrashedComments(c *gin.Context) {
+	page, pageSize := utils.ParsePagination(c, 1, 10, 100)
+
+	var comments []models.Comment
+	var total int64
+
+	query := trashScope(c, database.DB.Model(&models.Comment{}), "comments.user_id = ?")
+	query.Count(&total)
+
+	offset := (page - 1) * pageSize
+	if err := query.Preload("User").
+		Order("deleted_at DESC").
+		Limit(pageSize).
+		Offset(offset).
+		Find(&comments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get trash"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(CommentListResponse{
+		Comments:   comments,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: utils.CalculateTotalPages(total, pageSize),
+	}))
+}
+
+func (h *TrashHandler) RestoreComment(c *gin.Context) {
+	id, ok := utils.ParseUintParam(c, "id", "Invalid comment ID")
+	if !ok {
+		return
+	}
+
+	var comment models.Comment
+	if !h.findDeleted(c, &comment, id, "comment") {
+		return
+	}
+
+	if !checkCommentPermission(c, comment) {
+		return
+	}
+
+	if err := database.DB.Unscoped().Model(&comment).Update("deleted_at", nil).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to restore comment"))
+		return
+	}
+
+	database.DB.Preload("User").First(&comment, comment.ID)
+	c.JSON(http.StatusOK, utils.SuccessResponse(comment))
+}
+
+func (h *TrashHandler) PurgeComment(c *gin.Context) {
+	id, ok := utils.ParseUintParam(c, "id", "Invalid comment ID")
+	if !ok {
+		return
+	}
+
+	var comment models.Comment
+	if !h.findDeleted(c, &comment, id, "comment") {
+		return
+	}
+
+	if !checkCommentPermission(c, comment) {
+		return
+	}
+
+	// Purging cascades to the whole subtree, so authors may only purge their
+	// own threads; replies from anyone else are left for an admin to judge.
+	if userRole, _ := c.Get("userRole"); userRole.(string) != "admin" {
+		var foreign int64
+		if err := database.DB.Unscoped().Model(&models.Comment{}).
+			Where("path LIKE ? AND user_id <> ?", comment.Path+"_%", comment.UserID).
+			Count(&foreign).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to purge comment"))
+			return
+		}
+		if foreign > 0 {
+			c.JSON(http.StatusForbidden, utils.ErrorResponse("comment has replies from other users"))
+			return
+		}
+	}
+
+	// The guard runs in the DELETE itself so a reply posted after the checks
+	// above still blocks the cascade.
+	result := database.DB.Unscoped().
+		Where("NOT EXISTS (SELECT 1 FROM comments descendants WHERE descendants.path LIKE comments.path || '_%' AND descendants.deleted_at IS NULL)").
+		Delete(&comment)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to purge comment"))
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusConflict, utils.ErrorResponse("comment still has replies"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.MessageResponse("Comment permanently deleted"))
+}
@@ -0,0 +1,445 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+	"travel-blog-backend/internal/database"
+	"travel-blog-backend/internal/models"
+	"travel-blog-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type TripHandler struct{}
+
+func NewTripHandler() *TripHandler {
+	return &TripHandler{}
+}
+
+type TripStopRequest struct {
+	Name          string  `json:"name" binding:"required_without=PlaceID"`
+	Country       *string `json:"country"`
+	PlaceID       *uint   `json:"placeId"`
+	ArrivalDate   string  `json:"arrivalDate" binding:"required,datetime=2006-01-02"`
+	DepartureDate *string `json:"departureDate" binding:"omitempty,datetime=2006-01-02"`
+	Notes         *string `json:"notes"`
+}
+
+type CreateTripRequest struct {
+	Title         string            `json:"title" binding:"required"`
+	Description   *string           `json:"description"`
+	CoverImageURL *string           `json:"coverImageUrl"`
+	StartDate     string            `json:"startDate" binding:"required,datetime=2006-01-02"`
+	EndDate       string            `json:"endDate" binding:"required,datetime=2006-01-02"`
+	Stops         []TripStopRequest `json:"stops" binding:"dive"`
+}
+
+type UpdateTripRequest struct {
+	Title         *string `json:"title"`
+	Description   *string `json:"description"`
+	CoverImageURL *string `json:"coverImageUrl"`
+	StartDate     *string `json:"startDate" binding:"omitempty,datetime=2006-01-02"`
+	EndDate       *string `json:"endDate" binding:"omitempty,datetime=2006-01-02"`
+}
+
+type ReplaceStopsRequest struct {
+	Stops []TripStopRequest `json:"stops" binding:"dive"`
+}
+
+type TripListResponse struct {
+	Trips      []models.Trip `json:"trips"`
+	Total      int64         `json:"total"`
+	Page       int           `json:"page"`
+	PageSize   int           `json:"pageSize"`
+	TotalPages int           `json:"totalPages"`
+}
+
+type TripSummaryResponse struct {
+	TripID         uint      `json:"tripId"`
+	StartDate      time.Time `json:"startDate"`
+	EndDate        time.Time `json:"endDate"`
+	TotalDays      int       `json:"totalDays"`
+	Countries      []string  `json:"countries"`
+	CountriesCount int       `json:"countriesCount"`
+	StopsCount     int       `json:"stopsCount"`
+	PostsCount     int64     `json:"postsCount"`
+}
+
+func checkTripPermission(c *gin.Context, trip models.Trip) bool {
+	userID, _ := c.Get("userID")
+	userRole, _ := c.Get("userRole")
+	if trip.UserID != userID.(uint) && userRole.(string) != "admin" {
+		c.JSON(http.StatusForbidden, utils.ErrorResponse("permission denied"))
+		return false
+	}
+	return true
+}
+
+func (h *TripHandler) findTrip(c *gin.Context, id uint, trip *models.Trip) bool {
+	if err := database.DB.First(trip, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("trip not found"))
+		} else {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get trip"))
+		}
+		return false
+	}
+	return true
+}
+
+func preloadStops(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC")
+}
+
+func (h *TripHandler) GetTripsByUser(c *gin.Context) {
+	userID, ok := utils.ParseUintParam(c, "userId", "Invalid user ID")
+	if !ok {
+		return
+	}
+
+	page, pageSize := utils.ParsePagination(c, 1, 10, 100)
+
+	var trips []models.Trip
+	var total int64
+
+	query := database.DB.Model(&models.Trip{}).Where("user_id = ?", userID)
+	query.Count(&total)
+
+	offset := (page - 1) * pageSize
+	if err := query.Preload("User").
+		Order("start_date DESC").
+		Limit(pageSize).
+		Offset(offset).
+		Find(&trips).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get trips"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(TripListResponse{
+		Trips:      trips,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: utils.CalculateTotalPages(total, pageSize),
+	}))
+}
+
+func (h *TripHandler) GetTrip(c *gin.Context) {
+	id, ok := utils.ParseUintParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	var trip models.Trip
+	if err := database.DB.Preload("User").Preload("Stops", preloadStops).First(&trip, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("trip not found"))
+		} else {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get trip"))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(trip))
+}
+
+func (h *TripHandler) CreateTrip(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("User not authenticated"))
+		return
+	}
+
+	var req CreateTripRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request data: "+err.Error()))
+		return
+	}
+
+	startDate, _ := utils.ParseDate(req.StartDate)
+	endDate, _ := utils.ParseDate(req.EndDate)
+	if endDate.Before(startDate) {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("endDate must not be before startDate"))
+		return
+	}
+
+	stops, err := buildTripStops(req.Stops, startDate, endDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+
+	trip := models.Trip{
+		Title:         req.Title,
+		Description:   req.Description,
+		CoverImageURL: req.CoverImageURL,
+		StartDate:     startDate,
+		EndDate:       endDate,
+		UserID:        userID.(uint),
+		Stops:         stops,
+	}
+
+	if err := database.DB.Create(&trip).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to create trip"))
+		return
+	}
+
+	database.DB.Preload("User").Preload("Stops", preloadStops).First(&trip, trip.ID)
+	c.JSON(http.StatusCreated, utils.SuccessResponse(trip))
+}
+
+func (h *TripHandler) UpdateTrip(c *gin.Context) {
+	id, ok := utils.ParseUintParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	var req UpdateTripRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request data: "+err.Error()))
+		return
+	}
+
+	var trip models.Trip
+	if !h.findTrip(c, id, &trip) {
+		return
+	}
+
+	if !checkTripPermission(c, trip) {
+		return
+	}
+
+	updates := make(map[string]interface{})
+	if req.Title != nil {
+		updates["title"] = *req.Title
+	}
+	if req.Description != nil {
+		updates["description"] = req.Description
+	}
+	if req.CoverImageURL != nil {
+		updates["cover_image_url"] = req.CoverImageURL
+	}
+
+	startDate, endDate := trip.StartDate, trip.EndDate
+	if req.StartDate != nil {
+		startDate, _ = utils.ParseDate(*req.StartDate)
+		updates["start_date"] = startDate
+	}
+	if req.EndDate != nil {
+		endDate, _ = utils.ParseDate(*req.EndDate)
+		updates["end_date"] = endDate
+	}
+	if endDate.Before(startDate) {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("endDate must not be before startDate"))
+		return
+	}
+
+	// Existing stops must still fit the trip, as buildTripStops requires.
+	if req.StartDate != nil || req.EndDate != nil {
+		var outside int64
+		if err := database.DB.Model(&models.TripStop{}).
+			Where("trip_id = ? AND (arrival_date < ? OR arrival_date > ? OR departure_date > ?)", trip.ID, startDate, endDate, endDate).
+			Count(&outside).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to update trip"))
+			return
+		}
+		if outside > 0 {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("trip dates must cover the dates of all its stops"))
+			return
+		}
+	}
+
+	if err := database.DB.Model(&trip).Updates(updates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to update trip"))
+		return
+	}
+
+	database.DB.Preload("User").Preload("Stops", preloadStops).First(&trip, trip.ID)
+	c.JSON(http.StatusOK, utils.SuccessResponse(trip))
+}
+
+func (h *TripHandler) DeleteTrip(c *gin.Context) {
+	id, ok := utils.ParseUintParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	var trip models.Trip
+	if !h.findTrip(c, id, &trip) {
+		return
+	}
+
+	if !checkTripPermission(c, trip) {
+		return
+	}
+
+	if err := database.DB.Delete(&trip).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to delete trip"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.MessageResponse("Trip deleted successfully"))
+}
+
+func (h *TripHandler) ReplaceStops(c *gin.Context) {
+	id, ok := utils.ParseUintParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	var req ReplaceStopsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request data: "+err.Error()))
+		return
+	}
+
+	var trip models.Trip
+	if !h.findTrip(c, id, &trip) {
+		return
+	}
+
+	if !checkTripPermission(c, trip) {
+		return
+	}
+
+	stops, err := buildTripStops(req.Stops, trip.StartDate, trip.EndDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		return
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("trip_id = ?", trip.ID).Delete(&models.TripStop{}).Error; err != nil {
+			return err
+		}
+		if len(stops) == 0 {
+			return nil
+		}
+		for i := range stops {
+			stops[i].TripID = trip.ID
+		}
+		return tx.Create(&stops).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to update stops"))
+		return
+	}
+
+	database.DB.Preload("User").Preload("Stops", preloadStops).First(&trip, trip.ID)
+	c.JSON(http.StatusOK, utils.SuccessResponse(trip))
+}
+
+func (h *TripHandler) GetTripSummary(c *gin.Context) {
+	id, ok := utils.ParseUintParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	var trip models.Trip
+	if err := database.DB.Preload("Stops", preloadStops).First(&trip, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("trip not found"))
+		} else {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get trip"))
+		}
+		return
+	}
+
+	var postsCount int64
+	if err := database.DB.Model(&models.Post{}).
+		Scopes(visiblePostsScope(c, true)).
+		Where("trip_id = ? AND status = ?", trip.ID, models.StatusPublished).
+		Count(&postsCount).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to count posts"))
+		return
+	}
+
+	countries := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, stop := range trip.Stops {
+		if stop.Country == nil {
+			continue
+		}
+		country := strings.TrimSpace(*stop.Country)
+		key := strings.ToLower(country)
+		if country == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		countries = append(countries, country)
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(TripSummaryResponse{
+		TripID:         trip.ID,
+		StartDate:      trip.StartDate,
+		EndDate:        trip.EndDate,
+		TotalDays:      utils.DaysBetween(trip.StartDate, trip.EndDate),
+		Countries:      countries,
+		CountriesCount: len(countries),
+		StopsCount:     len(trip.Stops),
+		PostsCount:     postsCount,
+	}))
+}
+
+func buildTripStops(reqs []TripStopRequest, startDate, endDate time.Time) ([]models.TripStop, error) {
+	stops := make([]models.TripStop, 0, len(reqs))
+	for i, req := range reqs {
+		arrival, _ := utils.ParseDate(req.ArrivalDate)
+		if arrival.Before(startDate) || arrival.After(endDate) {
+			return nil, errors.New("stop arrivalDate must be within the trip dates")
+		}
+
+		stop := models.TripStop{
+			Position:    i + 1,
+			Name:        req.Name,
+			Country:     req.Country,
+			PlaceID:     req.PlaceID,
+			ArrivalDate: arrival,
+			Notes:       req.Notes,
+		}
+
+		if req.PlaceID != nil {
+			if err := fillStopFromPlace(&stop, *req.PlaceID); err != nil {
+				return nil, err
+			}
+		}
+
+		if req.DepartureDate != nil {
+			departure, _ := utils.ParseDate(*req.DepartureDate)
+			if departure.Before(arrival) || departure.After(endDate) {
+				return nil, errors.New("stop departureDate must be between arrivalDate and the trip end date")
+			}
+			stop.DepartureDate = &departure
+		}
+
+		stops = append(stops, stop)
+	}
+	return stops, nil
+}
+
+// fillStopFromPlace completes a stop's name and country from the referenced
+// place when the client left them out.
+func fillStopFromPlace(stop *models.TripStop, placeID uint) error {
+	var place models.Place
+	if err := database.DB.First(&place, placeID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("stop place not found")
+		}
+		return err
+	}
+
+	if stop.Name == "" {
+		stop.Name = place.Name
+	}
+	if stop.Country == nil {
+		country, err := placeCountryName(place.ID)
+		if err != nil {
+			return err
+		}
+		stop.Country = country
+	}
+	return nil
+}
@@ -23,3 +23,48 @@ func (h *UserHandler) GetUsersCount(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, utils.SuccessResponse(gin.H{"total": total}))
 }
+
+type SetAvatarRequest struct {
+	MediaID uint `json:"mediaId" binding:"required"`
+}
+
+func (h *UserHandler) SetAvatar(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("User not authenticated"))
+		return
+	}
+
+	var req SetAvatarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request data: "+err.Error()))
+		return
+	}
+
+	var item models.Media
+	if !findMedia(c, req.MediaID, &item) {
+		return
+	}
+	if item.UserID != userID.(uint) {
+		c.JSON(http.StatusForbidden, utils.ErrorResponse("permission denied"))
+		return
+	}
+
+	// Avatars are shown small, so prefer the smallest generated variant.
+	avatar := item.URL
+	if len(item.Variants) > 0 {
+		avatar = item.Variants[0].URL
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, userID.(uint)).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get user"))
+		return
+	}
+	if err := database.DB.Model(&user).Update("avatar", avatar).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to update avatar"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(user))
+}
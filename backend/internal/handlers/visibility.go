@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"travel-blog-backend/internal/database"
+	"travel-blog-backend/internal/models"
+	"travel-blog-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// postVisibilityColumns are the columns canViewPost needs.
+var postVisibilityColumns = []string{"id", "user_id", "status", "visibility", "hidden_at"}
+
+func parsePostVisibility(value string) (models.PostVisibility, bool) {
+	switch models.PostVisibility(value) {
+	case "", models.VisibilityPublic:
+		return models.VisibilityPublic, true
+	case models.VisibilityUnlisted:
+		return models.VisibilityUnlisted, true
+	case models.VisibilityFollowers:
+		return models.VisibilityFollowers, true
+	case models.VisibilityPrivate:
+		return models.VisibilityPrivate, true
+	}
+	return "", false
+}
+
+func isFollowing(followerID, followingID uint) (bool, error) {
+	var count int64
+	err := database.DB.Model(&models.Follow{}).
+		Where("follower_id = ? AND following_id = ?", followerID, followingID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// canViewPost reports whether the current reader may open post. Its authors
+// and admins see everything. Others only see published posts that are public
+// or unlisted, or followers-only posts of creators they follow, unless the
+// post was hidden after being reported.
+func canViewPost(c *gin.Context, post models.Post) (bool, error) {
+	published := post.Status == models.StatusPublished && post.HiddenAt == nil
+	if published && (post.Visibility == models.VisibilityPublic || post.Visibility == models.VisibilityUnlisted) {
+		return true, nil
+	}
+
+	userID, signedIn := c.Get("userID")
+	if !signedIn {
+		return false, nil
+	}
+	userRole, _ := c.Get("userRole")
+	if userRole.(string) == "admin" {
+		return true, nil
+	}
+
+	role, err := postAuthorRole(post, userID.(uint))
+	if err != nil || role != "" {
+		return role != "", err
+	}
+
+	if published && post.Visibility == models.VisibilityFollowers {
+		return isFollowing(userID.(uint), post.UserID)
+	}
+	return false, nil
+}
+
+// checkPostVisible writes an error response and returns false unless the
+// current reader may open post. Hidden posts are reported as missing rather
+// than forbidden so their IDs cannot be probed.
+func checkPostVisible(c *gin.Context, post models.Post) bool {
+	visible, err := canViewPost(c, post)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get post"))
+		return false
+	}
+	if !visible {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("post not found"))
+		return false
+	}
+	return true
+}
+
+// checkPostIDVisible loads the post with the given ID and checks that the
+// current reader may open it.
+func checkPostIDVisible(c *gin.Context, id uint) bool {
+	var post models.Post
+	if err := database.DB.Select(postVisibilityColumns).First(&post, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("post not found"))
+		} else {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to get post"))
+		}
+		return false
+	}
+	return checkPostVisible(c, post)
+}
+
+// visiblePostsScope restricts a query on posts to those the current reader
+// may see by their visibility. Listings pass listed to also leave out other
+// authors' unlisted posts, which are only reachable by direct link. Statuses
+// are filtered separately. Posts hidden after being reported are left out
+// for everyone but their authors and admins.
+func visiblePostsScope(c *gin.Context, listed bool) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		visibilities := []models.PostVisibility{models.VisibilityPublic}
+		if !listed {
+			visibilities = append(visibilities, models.VisibilityUnlisted)
+		}
+
+		userID, signedIn := c.Get("userID")
+		if !signedIn {
+			return db.Where("posts.visibility IN ? AND posts.hidden_at IS NULL", visibilities)
+		}
+
+		userRole, _ := c.Get("userRole")
+		if userRole.(string) == "admin" {
+			if listed {
+				return db.Where("(posts.visibility <> ? OR "+authoredPostsSQL+")", models.VisibilityUnlisted, userID.(uint))
+			}
+			return db
+		}
+
+		return db.Where(`((posts.hidden_at IS NULL AND (posts.visibility IN ? OR (posts.visibility = ? AND posts.user_id IN (
+			SELECT following_id FROM follows WHERE follower_id = ?)))) OR `+authoredPostsSQL+`)`,
+			visibilities, models.VisibilityFollowers, userID.(uint), userID.(uint))
+	}
+}
@@ -0,0 +1,422 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"travel-blog-backend/internal/config"
+	"travel-blog-backend/internal/database"
+	"travel-blog-backend/internal/models"
+	"travel-blog-backend/internal/spam"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pressly/goose/v3"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var (
+	testDBOnce sync.Once
+	testDBErr  error
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	os.Exit(m.Run())
+}
+
+// openTestDB points database.DB at the database named by TEST_DATABASE_URL,
+// migrated to the latest schema and emptied, and skips the test when the
+// variable is not set. The database is wiped, so never point it at real data.
+func openTestDB(t *testing.T) {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	testDBOnce.Do(func() {
+		config.LoadConfig()
+		config.AppConfig.DatabaseURL = dsn
+
+		database.DB, testDBErr = gorm.Open(postgres.Open(dsn), &gorm.Config{
+			Logger: logger.Default.LogMode(logger.Silent),
+		})
+		if testDBErr != nil {
+			return
+		}
+		sqlDB, err := database.DB.DB()
+		if err != nil {
+			testDBErr = err
+			return
+		}
+		goose.SetDialect("postgres")
+		testDBErr = goose.Up(sqlDB, "../../migrations")
+	})
+	if testDBErr != nil {
+		t.Fatalf("failed to open test database: %v", testDBErr)
+	}
+
+	if err := database.DB.Exec("TRUNCATE users, view_salts RESTART IDENTITY CASCADE").Error; err != nil {
+		t.Fatalf("failed to reset test database: %v", err)
+	}
+}
+
+// testReader is someone reading posts. A zero id stands for an anonymous
+// reader.
+type testReader struct {
+	name string
+	id   uint
+	role string
+}
+
+// testContext returns a context for a request by reader, set up the way the
+// auth middlewares would.
+func testContext(reader testReader, method, target, body string) (*gin.Context, *httptest.ResponseRecorder) {
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		c.Request.Header.Set("Content-Type", "application/json")
+	}
+	if reader.id != 0 {
+		c.Set("userID", reader.id)
+		c.Set("userRole", reader.role)
+	}
+	return c, recorder
+}
+
+// The posts of the visibility matrix, by name.
+var visibilityPostNames = []string{"public", "unlisted", "followers", "private", "draft", "hidden"}
+
+// visibilityPost returns a post by user in the state given by name.
+func visibilityPost(name string, userID uint) models.Post {
+	now := time.Now()
+	post := models.Post{
+		Title:       name,
+		Content:     "Content of the " + name + " post",
+		UserID:      userID,
+		Status:      models.StatusPublished,
+		Visibility:  models.VisibilityPublic,
+		PublishedAt: &now,
+	}
+	switch name {
+	case "unlisted":
+		post.Visibility = models.VisibilityUnlisted
+	case "followers":
+		post.Visibility = models.VisibilityFollowers
+	case "private":
+		post.Visibility = models.VisibilityPrivate
+	case "draft":
+		post.Status = models.StatusDraft
+		post.PublishedAt = nil
+	case "hidden":
+		post.HiddenAt = &now
+	}
+	return post
+}
+
+func TestCanViewPostWithoutLookups(t *testing.T) {
+	// Anonymous readers and admins are decided without touching the
+	// database, as are published public and unlisted posts.
+	readers := []testReader{
+		{name: "anonymous"},
+		{name: "admin", id: 99, role: "admin"},
+		{name: "user", id: 98, role: "user"},
+	}
+	want := map[string]map[string]bool{
+		"anonymous": {"public": true, "unlisted": true, "followers": false, "private": false, "draft": false, "hidden": false},
+		"admin":     {"public": true, "unlisted": true, "followers": true, "private": true, "draft": true, "hidden": true},
+		"user":      {"public": true, "unlisted": true},
+	}
+
+	for _, reader := range readers {
+		for _, name := range visibilityPostNames {
+			expected, ok := want[reader.name][name]
+			if !ok {
+				continue
+			}
+			t.Run(reader.name+"/"+name, func(t *testing.T) {
+				post := visibilityPost(name, 1)
+				post.ID = 1
+				c, _ := testContext(reader, http.MethodGet, "/", "")
+				got, err := canViewPost(c, post)
+				if err != nil {
+					t.Fatalf("canViewPost: %v", err)
+				}
+				if got != expected {
+					t.Errorf("canViewPost = %v, want %v", got, expected)
+				}
+			})
+		}
+	}
+}
+
+// visibilityFixture is an author with one post in every state of
+// visibilityPostNames, and readers related to them in every way that
+// matters: a follower, a stranger, a co-author of every post and an admin.
+type visibilityFixture struct {
+	readers []testReader
+	posts   map[string]models.Post
+}
+
+func newVisibilityFixture(t *testing.T) visibilityFixture {
+	t.Helper()
+
+	createUser := func(name string, role models.UserRole) models.User {
+		user := models.User{Email: name + "@example.com", Username: name, Password: "x", Role: role}
+		if err := database.DB.Create(&user).Error; err != nil {
+			t.Fatalf("failed to create user %s: %v", name, err)
+		}
+		return user
+	}
+	author := createUser("author", models.RoleUser)
+	follower := createUser("follower", models.RoleUser)
+	stranger := createUser("stranger", models.RoleUser)
+	coauthor := createUser("coauthor", models.RoleUser)
+	admin := createUser("admin", models.RoleAdmin)
+
+	if err := database.DB.Create(&models.Follow{FollowerID: follower.ID, FollowingID: author.ID}).Error; err != nil {
+		t.Fatalf("failed to create follow: %v", err)
+	}
+
+	now := time.Now()
+	fixture := visibilityFixture{
+		readers: []testReader{
+			{name: "anonymous"},
+			{name: "follower", id: follower.ID, role: "user"},
+			{name: "stranger", id: stranger.ID, role: "user"},
+			{name: "coauthor", id: coauthor.ID, role: "user"},
+			{name: "admin", id: admin.ID, role: "admin"},
+		},
+		posts: make(map[string]models.Post),
+	}
+	for _, name := range visibilityPostNames {
+		post := visibilityPost(name, author.ID)
+		if err := database.DB.Create(&post).Error; err != nil {
+			t.Fatalf("failed to create post %s: %v", name, err)
+		}
+		authors := []models.PostAuthor{
+			{PostID: post.ID, UserID: author.ID, Role: models.PostAuthorOwner, AcceptedAt: &now},
+			{PostID: post.ID, UserID: coauthor.ID, Role: models.PostAuthorEditor, InvitedBy: &author.ID, AcceptedAt: &now},
+		}
+		if err := database.DB.Create(&authors).Error; err != nil {
+			t.Fatalf("failed to add authors to post %s: %v", name, err)
+		}
+		fixture.posts[name] = post
+	}
+	return fixture
+}
+
+// postNames returns the sorted titles of posts, which the fixture sets to
+// the post names.
+func postNames(posts []models.Post) []string {
+	names := make([]string, len(posts))
+	for i, post := range posts {
+		names[i] = post.Title
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedNames(names ...string) []string {
+	sorted := append([]string{}, names...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+func TestPostVisibility(t *testing.T) {
+	openTestDB(t)
+	fixture := newVisibilityFixture(t)
+
+	// Which posts each reader may open.
+	canOpen := map[string][]string{
+		"anonymous": {"public", "unlisted"},
+		"follower":  {"public", "unlisted", "followers"},
+		"stranger":  {"public", "unlisted"},
+		"coauthor":  visibilityPostNames,
+		"admin":     visibilityPostNames,
+	}
+	// Which posts each reader finds through visiblePostsScope, which leaves
+	// statuses to the caller, with listed unset and set.
+	scoped := map[string][]string{
+		"anonymous": {"public", "unlisted", "draft"},
+		"follower":  {"public", "unlisted", "followers", "draft"},
+		"stranger":  {"public", "unlisted", "draft"},
+		"coauthor":  visibilityPostNames,
+		"admin":     visibilityPostNames,
+	}
+	listed := map[string][]string{
+		"anonymous": {"public", "draft"},
+		"follower":  {"public", "followers", "draft"},
+		"stranger":  {"public", "draft"},
+		"coauthor":  visibilityPostNames,
+		"admin":     {"public", "followers", "private", "draft", "hidden"},
+	}
+	// Which posts each reader finds in the published post listing and in
+	// their feed of followed authors.
+	listing := map[string][]string{
+		"anonymous": {"public"},
+		"follower":  {"public", "followers"},
+		"stranger":  {"public"},
+		"coauthor":  {"public", "unlisted", "followers", "private", "hidden"},
+		"admin":     {"public", "followers", "private", "hidden"},
+	}
+	feed := map[string][]string{
+		"follower": {"public", "followers"},
+		"stranger": {},
+		"coauthor": {},
+		"admin":    {},
+	}
+
+	t.Run("canViewPost", func(t *testing.T) {
+		for _, reader := range fixture.readers {
+			visible := make(map[string]bool)
+			for _, name := range canOpen[reader.name] {
+				visible[name] = true
+			}
+			for _, name := range visibilityPostNames {
+				t.Run(reader.name+"/"+name, func(t *testing.T) {
+					c, _ := testContext(reader, http.MethodGet, "/", "")
+					got, err := canViewPost(c, fixture.posts[name])
+					if err != nil {
+						t.Fatalf("canViewPost: %v", err)
+					}
+					if got != visible[name] {
+						t.Errorf("canViewPost = %v, want %v", got, visible[name])
+					}
+				})
+			}
+		}
+	})
+
+	t.Run("visiblePostsScope", func(t *testing.T) {
+		for _, reader := range fixture.readers {
+			for _, tc := range []struct {
+				listed bool
+				want   map[string][]string
+			}{
+				{false, scoped},
+				{true, listed},
+			} {
+				t.Run(fmt.Sprintf("%s/listed=%v", reader.name, tc.listed), func(t *testing.T) {
+					c, _ := testContext(reader, http.MethodGet, "/", "")
+					var posts []models.Post
+					if err := database.DB.Model(&models.Post{}).
+						Scopes(visiblePostsScope(c, tc.listed)).
+						Find(&posts).Error; err != nil {
+						t.Fatalf("query: %v", err)
+					}
+					got, want := postNames(posts), sortedNames(tc.want[reader.name]...)
+					if strings.Join(got, ",") != strings.Join(want, ",") {
+						t.Errorf("got %v, want %v", got, want)
+					}
+				})
+			}
+		}
+	})
+
+	t.Run("GetPost", func(t *testing.T) {
+		handler := NewPostHandler()
+		for _, reader := range fixture.readers {
+			visible := make(map[string]bool)
+			for _, name := range canOpen[reader.name] {
+				visible[name] = true
+			}
+			for _, name := range visibilityPostNames {
+				t.Run(reader.name+"/"+name, func(t *testing.T) {
+					id := fmt.Sprint(fixture.posts[name].ID)
+					c, recorder := testContext(reader, http.MethodGet, "/api/posts/"+id, "")
+					c.Params = gin.Params{{Key: "id", Value: id}}
+					handler.GetPost(c)
+
+					want := http.StatusNotFound
+					if visible[name] {
+						want = http.StatusOK
+					}
+					if recorder.Code != want {
+						t.Errorf("status = %d, want %d: %s", recorder.Code, want, recorder.Body)
+					}
+				})
+			}
+		}
+	})
+
+	listPosts := func(t *testing.T, reader testReader, target string, list func(*PostHandler, *gin.Context)) []string {
+		t.Helper()
+		c, recorder := testContext(reader, http.MethodGet, target, "")
+		list(NewPostHandler(), c)
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("status = %d: %s", recorder.Code, recorder.Body)
+		}
+		var response struct {
+			Data PostListResponse `json:"data"`
+		}
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatalf("invalid response: %v", err)
+		}
+		return postNames(response.Data.Posts)
+	}
+
+	t.Run("GetPosts", func(t *testing.T) {
+		for _, reader := range fixture.readers {
+			t.Run(reader.name, func(t *testing.T) {
+				got := listPosts(t, reader, "/api/posts", (*PostHandler).GetPosts)
+				if want := sortedNames(listing[reader.name]...); strings.Join(got, ",") != strings.Join(want, ",") {
+					t.Errorf("got %v, want %v", got, want)
+				}
+			})
+		}
+	})
+
+	t.Run("GetFeed", func(t *testing.T) {
+		for _, reader := range fixture.readers {
+			if reader.id == 0 {
+				continue
+			}
+			t.Run(reader.name, func(t *testing.T) {
+				got := listPosts(t, reader, "/api/posts/feed", (*PostHandler).GetFeed)
+				if want := sortedNames(feed[reader.name]...); strings.Join(got, ",") != strings.Join(want, ",") {
+					t.Errorf("got %v, want %v", got, want)
+				}
+			})
+		}
+	})
+
+	t.Run("CreateComment", func(t *testing.T) {
+		handler := NewCommentHandler(spam.New(config.AppConfig))
+		for _, reader := range fixture.readers {
+			if reader.id == 0 {
+				continue
+			}
+			visible := make(map[string]bool)
+			for _, name := range canOpen[reader.name] {
+				visible[name] = true
+			}
+			for _, name := range visibilityPostNames {
+				t.Run(reader.name+"/"+name, func(t *testing.T) {
+					id := fmt.Sprint(fixture.posts[name].ID)
+					c, recorder := testContext(reader, http.MethodPost, "/api/comments/post/"+id, `{"content":"Great post!"}`)
+					c.Params = gin.Params{{Key: "postId", Value: id}}
+					handler.CreateComment(c)
+
+					want := http.StatusNotFound
+					if visible[name] {
+						want = http.StatusCreated
+					}
+					if recorder.Code != want {
+						t.Errorf("status = %d, want %d: %s", recorder.Code, want, recorder.Body)
+					}
+				})
+			}
+		}
+	})
+}
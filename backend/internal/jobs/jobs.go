@@ -0,0 +1,67 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+	"travel-blog-backend/internal/config"
+)
+
+type job struct {
+	name     string
+	interval time.Duration
+	run      func(ctx context.Context) error
+}
+
+// Start launches the periodic background jobs. Every job must be safe to run
+// concurrently from several backend replicas. The jobs stop when ctx is done.
+func Start(ctx context.Context) {
+	jobs := []job{
+		{
+			name:     "publish scheduled posts",
+			interval: time.Duration(config.AppConfig.PublishCheckInterval) * time.Second,
+			run:      PublishScheduledPosts,
+		},
+		{
+			name:     "render pending posts",
+			interval: 10 * time.Minute,
+			run:      RenderPendingPosts,
+		},
+		{
+			name:     "flush post views",
+			interval: time.Duration(config.AppConfig.ViewFlushInterval) * time.Second,
+			run:      FlushViews,
+		},
+		{
+			name:     "prune view visitors",
+			interval: time.Hour,
+			run:      PruneViewVisitors,
+		},
+		{
+			name:     "purge trash",
+			interval: time.Hour,
+			run:      PurgeTrash,
+		},
+	}
+
+	for _, j := range jobs {
+		go loop(ctx, j)
+	}
+}
+
+func loop(ctx context.Context, j job) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := j.run(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Job %q failed: %v", j.name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"travel-blog-backend/internal/database"
+	"travel-blog-backend/internal/models"
+)
+
+// PublishScheduledPosts flips every scheduled post whose publish time has
+// passed to published. The status check and the update happen in a single
+// statement, so when several replicas race for the same rows Postgres' row
+// locking lets exactly one of them publish each post.
+func PublishScheduledPosts(ctx context.Context) error {
+	var ids []uint
+	err := database.DB.WithContext(ctx).Raw(`UPDATE posts
+		SET status = ?, published_at = now(), publish_at = NULL, updated_at = now()
+		WHERE status = ? AND publish_at <= now() AND deleted_at IS NULL
+		RETURNING id`, models.StatusPublished, models.StatusScheduled).Scan(&ids).Error
+	if err != nil {
+		return err
+	}
+
+	if len(ids) > 0 {
+		log.Printf("Published %d scheduled posts: %v", len(ids), ids)
+	}
+	return nil
+}
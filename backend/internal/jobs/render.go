@@ -0,0 +1,50 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"travel-blog-backend/internal/content"
+	"travel-blog-backend/internal/database"
+	"travel-blog-backend/internal/models"
+)
+
+const renderBatchSize = 100
+
+// RenderPendingPosts fills in the rendered HTML, table of contents and reading
+// time for posts created before server-side rendering existed.
+func RenderPendingPosts(ctx context.Context) error {
+	db := database.DB.WithContext(ctx)
+
+	for {
+		var posts []models.Post
+		if err := db.Unscoped().
+			Where("content_html IS NULL").
+			Order("id").
+			Limit(renderBatchSize).
+			Find(&posts).Error; err != nil {
+			return err
+		}
+		if len(posts) == 0 {
+			return nil
+		}
+
+		for i := range posts {
+			post := &posts[i]
+			if err := content.ApplyToPost(post); err != nil {
+				log.Printf("Failed to render post %d: %v", post.ID, err)
+				post.ContentHTML = ""
+			}
+			if err := db.Unscoped().Model(post).UpdateColumns(map[string]interface{}{
+				"content_html":      post.ContentHTML,
+				"toc":               post.TOC,
+				"reading_time":      post.ReadingTime,
+				"excerpt":           post.Excerpt,
+				"excerpt_generated": post.ExcerptGenerated,
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		log.Printf("Rendered %d posts", len(posts))
+	}
+}
@@ -0,0 +1,36 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+	"travel-blog-backend/internal/config"
+	"travel-blog-backend/internal/database"
+	"travel-blog-backend/internal/models"
+)
+
+// PurgeTrash permanently removes posts and comments that have been in the
+// trash for longer than the configured retention period. Comments that still
+// have replies are kept so the replies stay in their thread.
+func PurgeTrash(ctx context.Context) error {
+	cutoff := time.Now().AddDate(0, 0, -config.AppConfig.TrashRetentionDays)
+	db := database.DB.WithContext(ctx)
+
+	posts := db.Unscoped().Where("deleted_at < ?", cutoff).Delete(&models.Post{})
+	if posts.Error != nil {
+		return posts.Error
+	}
+
+	comments := db.Unscoped().
+		Where("deleted_at < ?", cutoff).
+		Where("NOT EXISTS (SELECT 1 FROM comments descendants WHERE descendants.path LIKE comments.path || '_%' AND descendants.deleted_at IS NULL)").
+		Delete(&models.Comment{})
+	if comments.Error != nil {
+		return comments.Error
+	}
+
+	if posts.RowsAffected > 0 || comments.RowsAffected > 0 {
+		log.Printf("Purged %d posts and %d comments from trash", posts.RowsAffected, comments.RowsAffected)
+	}
+	return nil
+}
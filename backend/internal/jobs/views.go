@@ -0,0 +1,27 @@
+package jobs
+
+import (
+	"context"
+	"time"
+	"travel-blog-backend/internal/database"
+	"travel-blog-backend/internal/models"
+	"travel-blog-backend/internal/views"
+)
+
+// FlushViews writes the post views buffered by this replica.
+func FlushViews(ctx context.Context) error {
+	return views.Flush(ctx)
+}
+
+// PruneViewVisitors deletes the visitor hashes and salts of past days. They
+// are only needed to deduplicate views within a day; the daily totals are
+// kept.
+func PruneViewVisitors(ctx context.Context) error {
+	today := time.Now().UTC().Format("2006-01-02")
+	db := database.DB.WithContext(ctx)
+
+	if err := db.Where("day < ?", today).Delete(&models.PostViewVisitor{}).Error; err != nil {
+		return err
+	}
+	return db.Where("day < ?", today).Delete(&models.ViewSalt{}).Error
+}
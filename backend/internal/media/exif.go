@@ -0,0 +1,200 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"time"
+)
+
+// Metadata holds the few EXIF values the server cares about. Everything else
+// in the EXIF block is discarded when the image is re-encoded.
+type Metadata struct {
+	Orientation int
+	Latitude    *float64
+	Longitude   *float64
+	TakenAt     *time.Time
+}
+
+const (
+	tagOrientation      = 0x0112
+	tagExifIFD          = 0x8769
+	tagGPSIFD           = 0x8825
+	tagDateTimeOriginal = 0x9003
+	tagGPSLatitudeRef   = 0x0001
+	tagGPSLatitude      = 0x0002
+	tagGPSLongitudeRef  = 0x0003
+	tagGPSLongitude     = 0x0004
+)
+
+type ifdEntry struct {
+	tag    uint16
+	typ    uint16
+	count  uint32
+	offset uint32
+	inline []byte
+}
+
+// ReadEXIF extracts orientation, capture time and GPS position from the EXIF
+// block of a JPEG file. Malformed or missing EXIF data yields an empty result.
+func ReadEXIF(data []byte) Metadata {
+	meta := Metadata{Orientation: 1}
+
+	tiff := findExifSegment(data)
+	if len(tiff) < 8 {
+		return meta
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return meta
+	}
+	if order.Uint16(tiff[2:4]) != 42 {
+		return meta
+	}
+
+	ifd0 := readIFD(tiff, order, order.Uint32(tiff[4:8]))
+	if e, ok := ifd0[tagOrientation]; ok {
+		if v, ok := shortValue(e, order); ok && v >= 1 && v <= 8 {
+			meta.Orientation = int(v)
+		}
+	}
+
+	if e, ok := ifd0[tagExifIFD]; ok {
+		exif := readIFD(tiff, order, e.offset)
+		if e, ok := exif[tagDateTimeOriginal]; ok {
+			if s, ok := asciiValue(tiff, e); ok {
+				if t, err := time.Parse("2006:01:02 15:04:05", s); err == nil {
+					meta.TakenAt = &t
+				}
+			}
+		}
+	}
+
+	if e, ok := ifd0[tagGPSIFD]; ok {
+		gps := readIFD(tiff, order, e.offset)
+		lat, latOK := coordinate(tiff, order, gps, tagGPSLatitude, tagGPSLatitudeRef, "S")
+		lon, lonOK := coordinate(tiff, order, gps, tagGPSLongitude, tagGPSLongitudeRef, "W")
+		if latOK && lonOK && lat >= -90 && lat <= 90 && lon >= -180 && lon <= 180 && (lat != 0 || lon != 0) {
+			meta.Latitude = &lat
+			meta.Longitude = &lon
+		}
+	}
+
+	return meta
+}
+
+func findExifSegment(data []byte) []byte {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil
+	}
+
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return nil
+		}
+		marker := data[i+1]
+		if marker == 0xD8 || (marker >= 0xD0 && marker <= 0xD7) || marker == 0x01 {
+			i += 2
+			continue
+		}
+		if marker == 0xDA || marker == 0xD9 {
+			return nil
+		}
+
+		length := int(binary.BigEndian.Uint16(data[i+2 : i+4]))
+		if length < 2 || i+2+length > len(data) {
+			return nil
+		}
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return segment[6:]
+		}
+		i += 2 + length
+	}
+	return nil
+}
+
+func readIFD(tiff []byte, order binary.ByteOrder, offset uint32) map[uint16]ifdEntry {
+	entries := make(map[uint16]ifdEntry)
+	if uint64(offset)+2 > uint64(len(tiff)) {
+		return entries
+	}
+
+	count := int(order.Uint16(tiff[offset:]))
+	start := int(offset) + 2
+	for i := 0; i < count; i++ {
+		pos := start + i*12
+		if pos+12 > len(tiff) {
+			break
+		}
+		e := ifdEntry{
+			tag:    order.Uint16(tiff[pos:]),
+			typ:    order.Uint16(tiff[pos+2:]),
+			count:  order.Uint32(tiff[pos+4:]),
+			offset: order.Uint32(tiff[pos+8:]),
+			inline: tiff[pos+8 : pos+12],
+		}
+		entries[e.tag] = e
+	}
+	return entries
+}
+
+func shortValue(e ifdEntry, order binary.ByteOrder) (uint16, bool) {
+	if e.typ != 3 || e.count < 1 {
+		return 0, false
+	}
+	return order.Uint16(e.inline), true
+}
+
+func asciiValue(tiff []byte, e ifdEntry) (string, bool) {
+	if e.typ != 2 || e.count == 0 {
+		return "", false
+	}
+	raw := e.inline
+	if e.count > 4 {
+		end := uint64(e.offset) + uint64(e.count)
+		if end > uint64(len(tiff)) {
+			return "", false
+		}
+		raw = tiff[e.offset:end]
+	}
+	if int(e.count) < len(raw) {
+		raw = raw[:e.count]
+	}
+	return string(bytes.TrimRight(raw, "\x00 ")), true
+}
+
+func coordinate(tiff []byte, order binary.ByteOrder, gps map[uint16]ifdEntry, valueTag, refTag uint16, negativeRef string) (float64, bool) {
+	e, ok := gps[valueTag]
+	if !ok || e.typ != 5 || e.count != 3 {
+		return 0, false
+	}
+	end := uint64(e.offset) + 24
+	if end > uint64(len(tiff)) {
+		return 0, false
+	}
+
+	var parts [3]float64
+	for i := range parts {
+		pos := e.offset + uint32(i*8)
+		num := order.Uint32(tiff[pos:])
+		den := order.Uint32(tiff[pos+4:])
+		if den == 0 {
+			return 0, false
+		}
+		parts[i] = float64(num) / float64(den)
+	}
+	value := parts[0] + parts[1]/60 + parts[2]/3600
+
+	if ref, ok := gps[refTag]; ok {
+		if s, ok := asciiValue(tiff, ref); ok && s == negativeRef {
+			value = -value
+		}
+	}
+	return value, true
+}
@@ -0,0 +1,221 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	maxPixels    = 50_000_000
+	jpegQuality  = 88
+	thumbQuality = 80
+)
+
+// ThumbnailWidths lists the widths of the resized copies generated for every
+// upload. Widths larger than the original image are skipped.
+var ThumbnailWidths = []int{320, 640, 1280}
+
+var (
+	ErrUnsupportedType = errors.New("unsupported image type")
+	ErrImageTooLarge   = errors.New("image dimensions are too large")
+)
+
+var allowedTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+type Thumbnail struct {
+	Width  int
+	Height int
+	Data   []byte
+}
+
+type Processed struct {
+	ContentType string
+	Extension   string
+	Data        []byte
+	Width       int
+	Height      int
+	Thumbnails  []Thumbnail
+	Metadata    Metadata
+}
+
+// DetectType sniffs the content type of data, ignoring whatever the client
+// claimed, and reports whether it is an accepted image format.
+func DetectType(data []byte) (string, bool) {
+	contentType := http.DetectContentType(data)
+	return contentType, allowedTypes[contentType]
+}
+
+// Process validates an uploaded image, applies its EXIF orientation and
+// re-encodes it so that no metadata from the original file survives. GIFs
+// are stored as uploaded to keep animations; they carry no EXIF block.
+func Process(data []byte) (*Processed, error) {
+	contentType, ok := DetectType(data)
+	if !ok {
+		return nil, ErrUnsupportedType
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedType
+	}
+	if cfg.Width*cfg.Height > maxPixels {
+		return nil, ErrImageTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedType
+	}
+
+	meta := Metadata{Orientation: 1}
+	if contentType == "image/jpeg" {
+		meta = ReadEXIF(data)
+		img = orient(img, meta.Orientation)
+	}
+
+	result := &Processed{Metadata: meta}
+	usePNG := contentType == "image/png" || (contentType == "image/webp" && !isOpaque(img))
+
+	switch {
+	case contentType == "image/gif":
+		result.ContentType, result.Extension, result.Data = "image/gif", ".gif", data
+	case usePNG:
+		result.ContentType, result.Extension = "image/png", ".png"
+		if result.Data, err = encodePNG(img); err != nil {
+			return nil, err
+		}
+	default:
+		result.ContentType, result.Extension = "image/jpeg", ".jpg"
+		if result.Data, err = encodeJPEG(img, jpegQuality); err != nil {
+			return nil, err
+		}
+	}
+
+	bounds := img.Bounds()
+	result.Width, result.Height = bounds.Dx(), bounds.Dy()
+
+	for _, width := range ThumbnailWidths {
+		if width >= result.Width {
+			break
+		}
+		height := result.Height * width / result.Width
+		if height < 1 {
+			height = 1
+		}
+
+		resized := image.NewRGBA(image.Rect(0, 0, width, height))
+		draw.CatmullRom.Scale(resized, resized.Bounds(), img, bounds, draw.Src, nil)
+
+		var thumb []byte
+		if usePNG {
+			thumb, err = encodePNG(resized)
+		} else {
+			thumb, err = encodeJPEG(resized, thumbQuality)
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.Thumbnails = append(result.Thumbnails, Thumbnail{Width: width, Height: height, Data: thumb})
+	}
+
+	return result, nil
+}
+
+// ThumbnailExtension returns the file extension used for thumbnails of a
+// processed image. Animated GIF thumbnails are stored as JPEG stills.
+func (p *Processed) ThumbnailExtension() (string, string) {
+	if p.ContentType == "image/png" {
+		return ".png", "image/png"
+	}
+	return ".jpg", "image/jpeg"
+}
+
+func encodeJPEG(img image.Image, quality int) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, flatten(img), &jpeg.Options{Quality: quality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func encodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := png.Encoder{CompressionLevel: png.BestCompression}
+	if err := encoder.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// flatten draws img onto a white background so transparent areas do not turn
+// black when encoded as JPEG.
+func flatten(img image.Image) image.Image {
+	if isOpaque(img) {
+		return img
+	}
+	out := image.NewRGBA(img.Bounds())
+	draw.Draw(out, out.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(out, out.Bounds(), img, img.Bounds().Min, draw.Over)
+	return out
+}
+
+func isOpaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+	return false
+}
+
+// orient rotates and flips img according to an EXIF orientation value.
+func orient(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	swap := orientation >= 5
+
+	outW, outH := w, h
+	if swap {
+		outW, outH = h, w
+	}
+	out := image.NewRGBA(image.Rect(0, 0, outW, outH))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2:
+				dx, dy = w-1-x, y
+			case 3:
+				dx, dy = w-1-x, h-1-y
+			case 4:
+				dx, dy = x, h-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = h-1-y, x
+			case 7:
+				dx, dy = h-1-y, w-1-x
+			case 8:
+				dx, dy = y, w-1-x
+			}
+			out.Set(dx, dy, img.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+	return out
+}
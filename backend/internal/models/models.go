@@ -1,6 +1,9 @@
 package models
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
 	"time"
 
 	"gorm.io/gorm"
@@ -18,6 +21,113 @@ type PostStatus string
 const (
 	StatusDraft     PostStatus = "draft"
 	StatusPublished PostStatus = "published"
+	StatusScheduled PostStatus = "scheduled"
+)
+
+type ContentFormat string
+
+const (
+	FormatMarkdown ContentFormat = "markdown"
+	FormatHTML     ContentFormat = "html"
+)
+
+type PlaceType string
+
+const (
+	PlaceContinent PlaceType = "continent"
+	PlaceCountry   PlaceType = "country"
+	PlaceRegion    PlaceType = "region"
+	PlaceCity      PlaceType = "city"
+	PlacePOI       PlaceType = "poi"
+)
+
+type PostVisibility string
+
+const (
+	VisibilityPublic    PostVisibility = "public"
+	VisibilityUnlisted  PostVisibility = "unlisted"
+	VisibilityFollowers PostVisibility = "followers"
+	VisibilityPrivate   PostVisibility = "private"
+)
+
+type PostAuthorRole string
+
+const (
+	PostAuthorOwner  PostAuthorRole = "owner"
+	PostAuthorEditor PostAuthorRole = "editor"
+)
+
+type CommentStatus string
+
+const (
+	CommentPending  CommentStatus = "pending"
+	CommentApproved CommentStatus = "approved"
+	CommentRejected CommentStatus = "rejected"
+	CommentSpam     CommentStatus = "spam"
+)
+
+// ModerationMode decides which new comments are held for a moderator
+// before they are shown.
+type ModerationMode string
+
+const (
+	ModerationOpen      ModerationMode = "open"
+	ModerationFirstTime ModerationMode = "first_time"
+	ModerationAll       ModerationMode = "all"
+)
+
+type ReportTargetType string
+
+const (
+	ReportPost    ReportTargetType = "post"
+	ReportComment ReportTargetType = "comment"
+	ReportUser    ReportTargetType = "user"
+)
+
+type ReportReason string
+
+const (
+	ReasonSpam           ReportReason = "spam"
+	ReasonHarassment     ReportReason = "harassment"
+	ReasonHate           ReportReason = "hate"
+	ReasonViolence       ReportReason = "violence"
+	ReasonSexual         ReportReason = "sexual"
+	ReasonMisinformation ReportReason = "misinformation"
+	ReasonCopyright      ReportReason = "copyright"
+	ReasonOther          ReportReason = "other"
+)
+
+type ReportStatus string
+
+const (
+	ReportOpen      ReportStatus = "open"
+	ReportDismissed ReportStatus = "dismissed"
+	ReportActioned  ReportStatus = "actioned"
+)
+
+// ReportAction is what an admin did about the reports on a target.
+type ReportAction string
+
+const (
+	ActionDismiss ReportAction = "dismiss"
+	ActionDelete  ReportAction = "delete"
+	ActionSuspend ReportAction = "suspend"
+)
+
+type NotificationType string
+
+const (
+	NotificationCommentPending NotificationType = "comment_pending"
+)
+
+type ReactionType string
+
+const (
+	ReactionLike  ReactionType = "like"
+	ReactionLove  ReactionType = "love"
+	ReactionWow   ReactionType = "wow"
+	ReactionLaugh ReactionType = "laugh"
+	ReactionSad   ReactionType = "sad"
 )
 
 type User struct {
@@ -30,6 +140,10 @@ type User struct {
 	Avatar    *string   `json:"avatar,omitempty"`
 	Bio       *string   `json:"bio,omitempty"`
 	Role      UserRole  `gorm:"type:varchar(20);default:'user'" json:"role"`
+	// SuspendedAt is set while the user is suspended, until SuspendedUntil
+	// or, when that is nil, until an admin lifts the suspension.
+	SuspendedAt    *time.Time `json:"suspendedAt,omitempty"`
+	SuspendedUntil *time.Time `json:"suspendedUntil,omitempty"`
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 	
@@ -37,19 +151,53 @@ type User struct {
 	Comments []Comment `gorm:"foreignKey:UserID" json:"comments,omitempty"`
 }
 
+// Suspended reports whether the user is suspended at now.
+func (u User) Suspended(now time.Time) bool {
+	return u.SuspendedAt != nil && (u.SuspendedUntil == nil || now.Before(*u.SuspendedUntil))
+}
+
 type Post struct {
 	ID        uint       `gorm:"primaryKey" json:"id"`
 	Title     string     `gorm:"not null" json:"title"`
 	Content   string     `gorm:"type:text;not null" json:"content"`
+	ContentFormat ContentFormat   `gorm:"type:varchar(20);default:'markdown'" json:"contentFormat"`
+	ContentHTML   string          `gorm:"type:text" json:"contentHtml"`
+	TOC           TableOfContents `gorm:"column:toc;type:jsonb" json:"toc"`
+	ReadingTime   int             `json:"readingTime"`
 	Excerpt   *string    `gorm:"type:text" json:"excerpt,omitempty"`
+	ExcerptGenerated bool `gorm:"not null;default:false" json:"-"`
 	ImageURL  *string    `json:"imageUrl,omitempty"`
+	Language  string     `gorm:"type:varchar(20);not null;default:'en'" json:"language"`
 	UserID    uint       `gorm:"not null;index" json:"userId"`
 	Status    PostStatus `gorm:"type:varchar(20);default:'published'" json:"status"`
+	Visibility PostVisibility `gorm:"type:varchar(20);not null;default:'public'" json:"visibility"`
+	PublishAt   *time.Time `json:"publishAt,omitempty"`
+	PublishedAt *time.Time `gorm:"index" json:"publishedAt,omitempty"`
+	TripID    *uint      `gorm:"index" json:"tripId,omitempty"`
+	PlaceID   *uint      `gorm:"index" json:"placeId,omitempty"`
+	SeriesID  *uint      `gorm:"index" json:"seriesId,omitempty"`
+	SeriesPosition *int  `json:"seriesPosition,omitempty"`
+	Tags      StringList `gorm:"type:jsonb;not null" json:"tags"`
+	ViewCount int64      `gorm:"not null;default:0" json:"viewCount"`
+	PreviewVersion int   `gorm:"not null;default:0" json:"-"`
+	CommentModeration *ModerationMode `gorm:"type:varchar(20)" json:"commentModeration,omitempty"`
+	HiddenAt  *time.Time `json:"hiddenAt,omitempty"`
 	CreatedAt time.Time  `gorm:"index" json:"createdAt"`
 	UpdatedAt time.Time  `json:"updatedAt"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"deletedAt,omitempty"`
+
+	Reactions    map[ReactionType]int64 `gorm:"-" json:"reactions"`
+	IsBookmarked *bool                  `gorm:"-" json:"isBookmarked,omitempty"`
+	Authors      []PostAuthor           `gorm:"-" json:"authors,omitempty"`
+	Navigation   *SeriesNavigation      `gorm:"-" json:"series,omitempty"`
+
+	// ContentLanguage is the language the title and content are returned in,
+	// either Language or one of the translations listed in Languages.
+	ContentLanguage string   `gorm:"-" json:"contentLanguage,omitempty"`
+	Languages       []string `gorm:"-" json:"languages,omitempty"`
 	
 	User     User      `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Place    *Place    `gorm:"foreignKey:PlaceID" json:"place,omitempty"`
 	Comments []Comment `gorm:"foreignKey:PostID" json:"comments,omitempty"`
 }
 
@@ -59,6 +207,21 @@ type Comment struct {
 	PostID    uint      `gorm:"not null;index" json:"postId"`
 	UserID    uint      `gorm:"not null;index" json:"userId"`
 	ParentID  *uint     `json:"parentId,omitempty"`
+	// Path lists the IDs from the thread's root down to the comment, as in
+	// "/12/40/41/", so a subtree is every comment whose path starts with
+	// its root's.
+	Path      string    `gorm:"type:text;not null;default:''" json:"-"`
+	Depth     int       `gorm:"not null;default:0" json:"depth"`
+	Status    CommentStatus `gorm:"type:varchar(20);not null;default:'approved'" json:"status"`
+	ModeratedBy *uint      `json:"moderatedBy,omitempty"`
+	ModeratedAt *time.Time `json:"moderatedAt,omitempty"`
+	SpamScore   float64    `gorm:"not null;default:0" json:"-"`
+	SpamReasons StringList `gorm:"type:jsonb;not null;default:'[]'" json:"-"`
+	// SpamTrained is the class, "spam" or "ham", the comment was last used
+	// to train the spam classifier as.
+	SpamTrained *string    `gorm:"type:varchar(10)" json:"-"`
+	HiddenAt    *time.Time `json:"hiddenAt,omitempty"`
+	EditedAt  *time.Time `json:"editedAt,omitempty"`
 	CreatedAt time.Time `gorm:"index" json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"deletedAt,omitempty"`
@@ -67,6 +230,84 @@ type Comment struct {
 	User     User          `gorm:"foreignKey:UserID" json:"user,omitempty"`
 	Parent   *Comment      `gorm:"foreignKey:ParentID" json:"parent,omitempty"`
 	Replies  []Comment     `gorm:"foreignKey:ParentID" json:"replies,omitempty"`
+
+	// ReplyCount is the number of direct replies, of which Replies may
+	// only hold the first page.
+	ReplyCount int `gorm:"-" json:"replyCount"`
+	// Spam reports the spam screening of the comment to moderators.
+	Spam *CommentSpamReport `gorm:"-" json:"spam,omitempty"`
+}
+
+type CommentSpamReport struct {
+	Score   float64  `json:"score"`
+	Reasons []string `json:"reasons"`
+}
+
+// CommentRevision keeps the content a comment had before an edit, for
+// moderators reviewing its history.
+type CommentRevision struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CommentID uint      `gorm:"not null;index" json:"commentId"`
+	UserID    uint      `gorm:"not null" json:"userId"`
+	Content   string    `gorm:"type:text;not null" json:"content"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// Notification tells a user about something that needs their attention,
+// such as a comment on their post waiting for moderation.
+type Notification struct {
+	ID        uint             `gorm:"primaryKey" json:"id"`
+	UserID    uint             `gorm:"not null;index" json:"userId"`
+	Type      NotificationType `gorm:"type:varchar(30);not null" json:"type"`
+	ActorID   *uint            `json:"actorId,omitempty"`
+	PostID    *uint            `json:"postId,omitempty"`
+	CommentID *uint            `json:"commentId,omitempty"`
+	ReadAt    *time.Time       `json:"readAt,omitempty"`
+	CreatedAt time.Time        `gorm:"index" json:"createdAt"`
+
+	Actor   *User    `gorm:"foreignKey:ActorID" json:"actor,omitempty"`
+	Post    *Post    `gorm:"foreignKey:PostID" json:"post,omitempty"`
+	Comment *Comment `gorm:"foreignKey:CommentID" json:"comment,omitempty"`
+}
+
+// Report flags a post, comment or user for admins to review. Each reporter
+// has at most one open report per target.
+type Report struct {
+	ID         uint             `gorm:"primaryKey" json:"id"`
+	ReporterID uint             `gorm:"not null;index" json:"reporterId"`
+	TargetType ReportTargetType `gorm:"type:varchar(20);not null" json:"targetType"`
+	TargetID   uint             `gorm:"not null" json:"targetId"`
+	Reason     ReportReason     `gorm:"type:varchar(30);not null" json:"reason"`
+	Details    *string          `gorm:"type:text" json:"details,omitempty"`
+	Status     ReportStatus     `gorm:"type:varchar(20);not null;default:'open'" json:"status"`
+	Action     *ReportAction    `gorm:"type:varchar(20)" json:"action,omitempty"`
+	ResolvedBy *uint            `json:"resolvedBy,omitempty"`
+	ResolvedAt *time.Time       `json:"resolvedAt,omitempty"`
+	CreatedAt  time.Time        `json:"createdAt"`
+	UpdatedAt  time.Time        `json:"updatedAt"`
+
+	Reporter User `gorm:"foreignKey:ReporterID" json:"reporter,omitempty"`
+}
+
+// SpamToken counts the spam and ham comments a token was seen in, for the
+// spam classifier.
+type SpamToken struct {
+	Token string `gorm:"primaryKey;type:varchar(100)" json:"token"`
+	Spam  int64  `gorm:"not null;default:0" json:"spam"`
+	Ham   int64  `gorm:"not null;default:0" json:"ham"`
+}
+
+// SpamCorpus counts the comments the spam classifier was trained on per
+// class.
+type SpamCorpus struct {
+	Class     string `gorm:"primaryKey;type:varchar(10)" json:"class"`
+	Documents int64  `gorm:"not null;default:0" json:"documents"`
+}
+
+func (SpamCorpus) TableName() string {
+	return "spam_corpora"
 }
 
 type RefreshToken struct {
@@ -79,3 +320,328 @@ type RefreshToken struct {
 	User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
 }
 
+
+type Trip struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	Title         string    `gorm:"not null" json:"title"`
+	Description   *string   `gorm:"type:text" json:"description,omitempty"`
+	CoverImageURL *string   `json:"coverImageUrl,omitempty"`
+	StartDate     time.Time `gorm:"type:date;not null" json:"startDate"`
+	EndDate       time.Time `gorm:"type:date;not null" json:"endDate"`
+	UserID        uint      `gorm:"not null;index" json:"userId"`
+	CreatedAt     time.Time `gorm:"index" json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+
+	User  User       `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Stops []TripStop `gorm:"foreignKey:TripID" json:"stops,omitempty"`
+}
+
+type TripStop struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	TripID        uint       `gorm:"not null;index" json:"tripId"`
+	Position      int        `gorm:"not null" json:"position"`
+	Name          string     `gorm:"not null" json:"name"`
+	Country       *string    `json:"country,omitempty"`
+	PlaceID       *uint      `gorm:"index" json:"placeId,omitempty"`
+	ArrivalDate   time.Time  `gorm:"type:date;not null" json:"arrivalDate"`
+	DepartureDate *time.Time `gorm:"type:date" json:"departureDate,omitempty"`
+	Notes         *string    `gorm:"type:text" json:"notes,omitempty"`
+}
+
+type Place struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Slug        string    `gorm:"uniqueIndex;not null" json:"slug"`
+	Name        string    `gorm:"not null;index" json:"name"`
+	Type        PlaceType `gorm:"type:varchar(20);not null" json:"type"`
+	ParentID    *uint     `gorm:"index" json:"parentId,omitempty"`
+	CountryCode *string   `gorm:"type:varchar(2)" json:"countryCode,omitempty"`
+	Latitude    *float64  `json:"latitude,omitempty"`
+	Longitude   *float64  `json:"longitude,omitempty"`
+
+	Parent   *Place  `gorm:"foreignKey:ParentID" json:"parent,omitempty"`
+	Children []Place `gorm:"foreignKey:ParentID" json:"children,omitempty"`
+}
+
+type StringList []string
+
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	return jsonValue(l)
+}
+
+func (l *StringList) Scan(value interface{}) error {
+	return jsonScan(value, l)
+}
+
+type TOCEntry struct {
+	Level int    `json:"level"`
+	Text  string `json:"text"`
+	ID    string `json:"id"`
+}
+
+type TableOfContents []TOCEntry
+
+func (t TableOfContents) Value() (driver.Value, error) {
+	if t == nil {
+		return "[]", nil
+	}
+	return jsonValue(t)
+}
+
+func (t *TableOfContents) Scan(value interface{}) error {
+	return jsonScan(value, t)
+}
+
+func jsonValue(v interface{}) (driver.Value, error) {
+	b, err := json.Marshal(v)
+	return string(b), err
+}
+
+func jsonScan(value interface{}, dest interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, dest)
+	case string:
+		return json.Unmarshal([]byte(v), dest)
+	}
+	return errors.New("unsupported type for JSON column")
+}
+
+type PostRevision struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	PostID        uint       `gorm:"not null;index" json:"postId"`
+	Number        int        `gorm:"not null" json:"number"`
+	UserID        uint       `gorm:"not null" json:"userId"`
+	Title         string     `gorm:"not null" json:"title"`
+	Content       string     `gorm:"type:text;not null" json:"content,omitempty"`
+	Excerpt       *string    `gorm:"type:text" json:"excerpt,omitempty"`
+	ImageURL      *string    `json:"imageUrl,omitempty"`
+	ChangedFields StringList `gorm:"type:jsonb;not null" json:"changedFields"`
+	RestoredFrom  *int       `json:"restoredFrom,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+
+	User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+type MediaVariant struct {
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Key    string `json:"key"`
+	URL    string `json:"url"`
+}
+
+type MediaVariants []MediaVariant
+
+func (v MediaVariants) Value() (driver.Value, error) {
+	if v == nil {
+		return "[]", nil
+	}
+	return jsonValue(v)
+}
+
+func (v *MediaVariants) Scan(value interface{}) error {
+	return jsonScan(value, v)
+}
+
+type Media struct {
+	ID          uint          `gorm:"primaryKey" json:"id"`
+	UserID      uint          `gorm:"not null;index" json:"userId"`
+	Key         string        `gorm:"uniqueIndex;not null" json:"key"`
+	URL         string        `gorm:"not null" json:"url"`
+	ContentType string        `gorm:"not null" json:"contentType"`
+	Size        int64         `gorm:"not null" json:"size"`
+	Width       int           `gorm:"not null" json:"width"`
+	Height      int           `gorm:"not null" json:"height"`
+	Variants    MediaVariants `gorm:"type:jsonb;not null" json:"variants"`
+	Latitude    *float64      `json:"latitude,omitempty"`
+	Longitude   *float64      `json:"longitude,omitempty"`
+	TakenAt     *time.Time    `json:"takenAt,omitempty"`
+	CreatedAt   time.Time     `gorm:"index" json:"createdAt"`
+}
+
+func (Media) TableName() string {
+	return "media"
+}
+
+type GalleryImage struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	PostID    *uint      `gorm:"index" json:"postId,omitempty"`
+	TripID    *uint      `gorm:"index" json:"tripId,omitempty"`
+	MediaID   uint       `gorm:"not null;index" json:"mediaId"`
+	Position  int        `gorm:"not null" json:"position"`
+	Caption   *string    `gorm:"type:text" json:"caption,omitempty"`
+	AltText   *string    `json:"altText,omitempty"`
+	TakenAt   *time.Time `json:"takenAt,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+
+	Media Media `gorm:"foreignKey:MediaID" json:"media"`
+}
+
+// TrackPoint is one recorded position. Segment numbers the GPX track
+// segment the point belongs to, so gaps in a recording are not joined up.
+type TrackPoint struct {
+	Latitude  float64    `json:"lat"`
+	Longitude float64    `json:"lon"`
+	Elevation *float64   `json:"ele,omitempty"`
+	Time      *time.Time `json:"time,omitempty"`
+	Segment   int        `json:"seg,omitempty"`
+}
+
+type TrackPoints []TrackPoint
+
+func (p TrackPoints) Value() (driver.Value, error) {
+	if p == nil {
+		return "[]", nil
+	}
+	return jsonValue(p)
+}
+
+func (p *TrackPoints) Scan(value interface{}) error {
+	return jsonScan(value, p)
+}
+
+type Track struct {
+	ID               uint        `gorm:"primaryKey" json:"id"`
+	PostID           *uint       `gorm:"index" json:"postId,omitempty"`
+	TripID           *uint       `gorm:"index" json:"tripId,omitempty"`
+	UserID           uint        `gorm:"not null;index" json:"userId"`
+	Name             string      `gorm:"not null" json:"name"`
+	Distance         float64     `gorm:"not null" json:"distance"`
+	ElevationGain    float64     `gorm:"not null" json:"elevationGain"`
+	Duration         int64       `gorm:"not null" json:"duration"`
+	StartedAt        *time.Time  `json:"startedAt,omitempty"`
+	EndedAt          *time.Time  `json:"endedAt,omitempty"`
+	MinLatitude      float64     `gorm:"not null" json:"minLatitude"`
+	MinLongitude     float64     `gorm:"not null" json:"minLongitude"`
+	MaxLatitude      float64     `gorm:"not null" json:"maxLatitude"`
+	MaxLongitude     float64     `gorm:"not null" json:"maxLongitude"`
+	PointCount       int         `gorm:"not null" json:"pointCount"`
+	Points           TrackPoints `gorm:"type:jsonb;not null" json:"-"`
+	SimplifiedPoints TrackPoints `gorm:"type:jsonb;not null" json:"-"`
+	CreatedAt        time.Time   `json:"createdAt"`
+}
+
+type PostReaction struct {
+	PostID    uint         `gorm:"primaryKey" json:"postId"`
+	UserID    uint         `gorm:"primaryKey" json:"userId"`
+	Type      ReactionType `gorm:"primaryKey;type:varchar(20)" json:"type"`
+	CreatedAt time.Time    `json:"createdAt"`
+
+	User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// PostTranslation holds the title and content of a post in another
+// language, rendered the same way as the original.
+type PostTranslation struct {
+	ID               uint            `gorm:"primaryKey" json:"id"`
+	PostID           uint            `gorm:"not null;uniqueIndex:idx_post_translations_post_language" json:"postId"`
+	Language         string          `gorm:"type:varchar(20);not null;uniqueIndex:idx_post_translations_post_language" json:"language"`
+	Title            string          `gorm:"not null" json:"title"`
+	Content          string          `gorm:"type:text;not null" json:"content"`
+	ContentFormat    ContentFormat   `gorm:"type:varchar(20);default:'markdown'" json:"contentFormat"`
+	ContentHTML      string          `gorm:"type:text" json:"contentHtml"`
+	TOC              TableOfContents `gorm:"column:toc;type:jsonb" json:"toc"`
+	ReadingTime      int             `json:"readingTime"`
+	Excerpt          *string         `gorm:"type:text" json:"excerpt,omitempty"`
+	ExcerptGenerated bool            `gorm:"not null;default:false" json:"-"`
+	CreatedAt        time.Time       `json:"createdAt"`
+	UpdatedAt        time.Time       `json:"updatedAt"`
+}
+
+// Series groups posts into numbered parts, ordered by their
+// SeriesPosition.
+type Series struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Title       string    `gorm:"not null" json:"title"`
+	Description *string   `gorm:"type:text" json:"description,omitempty"`
+	UserID      uint      `gorm:"not null;index" json:"userId"`
+	CreatedAt   time.Time `gorm:"index" json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+
+	PartsCount int64 `gorm:"->;-:migration" json:"partsCount"`
+
+	User  User   `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Parts []Post `gorm:"-" json:"parts,omitempty"`
+}
+
+func (Series) TableName() string {
+	return "series"
+}
+
+// SeriesNavigation places a post within its series. Parts are numbered
+// among the parts the reader can see.
+type SeriesNavigation struct {
+	ID       uint        `json:"id"`
+	Title    string      `json:"title"`
+	Part     int         `json:"part"`
+	Parts    int         `json:"parts"`
+	Previous *SeriesPart `json:"previous,omitempty"`
+	Next     *SeriesPart `json:"next,omitempty"`
+}
+
+type SeriesPart struct {
+	ID    uint   `json:"id"`
+	Title string `json:"title"`
+}
+
+// PostAuthor lists a user among the authors of a post. Rows start as
+// invitations and count once AcceptedAt is set. The post's creator always
+// has an accepted owner row.
+type PostAuthor struct {
+	PostID     uint           `gorm:"primaryKey" json:"postId"`
+	UserID     uint           `gorm:"primaryKey" json:"userId"`
+	Role       PostAuthorRole `gorm:"type:varchar(20);not null" json:"role"`
+	InvitedBy  *uint          `json:"invitedBy,omitempty"`
+	AcceptedAt *time.Time     `json:"acceptedAt,omitempty"`
+	CreatedAt  time.Time      `json:"createdAt"`
+
+	User User  `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Post *Post `gorm:"foreignKey:PostID" json:"post,omitempty"`
+}
+
+// Follow records that FollowerID follows FollowingID and may read their
+// followers-only posts.
+type Follow struct {
+	FollowerID  uint      `gorm:"primaryKey" json:"followerId"`
+	FollowingID uint      `gorm:"primaryKey" json:"followingId"`
+	CreatedAt   time.Time `json:"createdAt"`
+
+	Follower  User `gorm:"foreignKey:FollowerID" json:"follower,omitempty"`
+	Following User `gorm:"foreignKey:FollowingID" json:"following,omitempty"`
+}
+
+type BookmarkCollection struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;index" json:"userId"`
+	Name      string    `gorm:"not null" json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	BookmarksCount int64 `gorm:"->;-:migration" json:"bookmarksCount"`
+}
+
+type Bookmark struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	UserID       uint      `gorm:"not null;index" json:"userId"`
+	PostID       uint      `gorm:"not null;index" json:"postId"`
+	CollectionID *uint     `gorm:"index" json:"collectionId,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+
+	Post Post `gorm:"foreignKey:PostID" json:"post,omitempty"`
+}
+
+type ViewSalt struct {
+	Day  time.Time `gorm:"type:date;primaryKey"`
+	Salt []byte    `gorm:"not null"`
+}
+
+type PostViewVisitor struct {
+	PostID  uint      `gorm:"primaryKey"`
+	Day     time.Time `gorm:"type:date;primaryKey"`
+	Visitor string    `gorm:"primaryKey"`
+}
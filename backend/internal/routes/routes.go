@@ -1,25 +1,65 @@
 package routes
 
 import (
+	"errors"
 	"log"
 	"net/http"
 	"strings"
+	"time"
+	"travel-blog-backend/internal/config"
+	"travel-blog-backend/internal/database"
 	"travel-blog-backend/internal/handlers"
+	"travel-blog-backend/internal/models"
+	"travel-blog-backend/internal/spam"
+	"travel-blog-backend/internal/storage"
 	"travel-blog-backend/internal/utils"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 func SetupRoutes() *gin.Engine {
 	router := gin.Default()
+	router.MaxMultipartMemory = int64(config.AppConfig.MaxUploadMB) << 20
+
+	// Only the reverse proxy may set X-Forwarded-For; from anyone else it is
+	// ignored so clients cannot pick the IP that view counts are keyed on.
+	if err := router.SetTrustedProxies(config.AppConfig.TrustedProxies); err != nil {
+		log.Fatal("Invalid trusted proxies:", err)
+	}
+
+	store, err := storage.New(config.AppConfig)
+	if err != nil {
+		log.Fatal("Failed to initialize storage:", err)
+	}
+	if local, ok := store.(*storage.LocalStorage); ok && strings.HasPrefix(config.AppConfig.MediaBaseURL, "/") {
+		router.Static(config.AppConfig.MediaBaseURL, local.Dir())
+	}
 
 	router.Use(corsMiddleware())
 	router.Use(errorHandler())
 
 	authHandler := handlers.NewAuthHandler()
 	postHandler := handlers.NewPostHandler()
-	commentHandler := handlers.NewCommentHandler()
+	commentHandler := handlers.NewCommentHandler(spam.New(config.AppConfig))
 	userHandler := handlers.NewUserHandler()
+	tripHandler := handlers.NewTripHandler()
+	placeHandler := handlers.NewPlaceHandler()
+	revisionHandler := handlers.NewRevisionHandler()
+	trashHandler := handlers.NewTrashHandler()
+	mediaHandler := handlers.NewMediaHandler(store)
+	galleryHandler := handlers.NewGalleryHandler()
+	trackHandler := handlers.NewTrackHandler()
+	reactionHandler := handlers.NewReactionHandler()
+	bookmarkHandler := handlers.NewBookmarkHandler()
+	relatedHandler := handlers.NewRelatedHandler()
+	previewHandler := handlers.NewPreviewHandler()
+	followHandler := handlers.NewFollowHandler()
+	coAuthorHandler := handlers.NewCoAuthorHandler()
+	seriesHandler := handlers.NewSeriesHandler()
+	translationHandler := handlers.NewTranslationHandler()
+	notificationHandler := handlers.NewNotificationHandler()
+	reportHandler := handlers.NewReportHandler()
 
 	api := router.Group("/api")
 	{
@@ -33,31 +73,181 @@ func SetupRoutes() *gin.Engine {
 
 		posts := api.Group("/posts")
 		{
-			posts.GET("", postHandler.GetPosts)
+			posts.GET("", optionalAuthMiddleware(), postHandler.GetPosts)
 			posts.POST("", authMiddleware(), postHandler.CreatePost)
-			posts.GET("/user/:userId", postHandler.GetPostsByUser)
+			posts.GET("/user/:userId", optionalAuthMiddleware(), postHandler.GetPostsByUser)
+			posts.GET("/trending", optionalAuthMiddleware(), postHandler.GetTrendingPosts)
+			posts.GET("/trash", authMiddleware(), trashHandler.GetTrashedPosts)
+			posts.GET("/mine", authMiddleware(), postHandler.GetMyPosts)
+			posts.GET("/feed", authMiddleware(), postHandler.GetFeed)
+			posts.GET("/invitations", authMiddleware(), coAuthorHandler.GetInvitations)
+			posts.GET("/preview/:token", previewHandler.GetPreview)
 			
-			posts.GET("/:id", postHandler.GetPost)
+			posts.GET("/:id", optionalAuthMiddleware(), postHandler.GetPost)
+			posts.GET("/:id/related", optionalAuthMiddleware(), relatedHandler.GetRelatedPosts)
 			posts.PUT("/:id", authMiddleware(), postHandler.UpdatePost)
 			posts.DELETE("/:id", authMiddleware(), postHandler.DeletePost)
+			posts.POST("/:id/restore", authMiddleware(), trashHandler.RestorePost)
+			posts.DELETE("/:id/purge", authMiddleware(), trashHandler.PurgePost)
+
+			posts.GET("/:id/revisions", authMiddleware(), revisionHandler.GetRevisions)
+			posts.GET("/:id/revisions/diff", authMiddleware(), revisionHandler.DiffRevisions)
+			posts.GET("/:id/revisions/:number", authMiddleware(), revisionHandler.GetRevision)
+			posts.POST("/:id/revisions/:number/restore", authMiddleware(), revisionHandler.RestoreRevision)
+
+			posts.GET("/:id/authors", optionalAuthMiddleware(), coAuthorHandler.GetAuthors)
+			posts.POST("/:id/authors", authMiddleware(), coAuthorHandler.InviteAuthor)
+			posts.POST("/:id/authors/accept", authMiddleware(), coAuthorHandler.AcceptInvitation)
+			posts.PUT("/:id/authors/:userId", authMiddleware(), coAuthorHandler.UpdateAuthor)
+			posts.DELETE("/:id/authors/:userId", authMiddleware(), coAuthorHandler.RemoveAuthor)
+
+			posts.GET("/:id/translations", optionalAuthMiddleware(), translationHandler.GetTranslations)
+			posts.PUT("/:id/translations/:lang", authMiddleware(), translationHandler.PutTranslation)
+			posts.DELETE("/:id/translations/:lang", authMiddleware(), translationHandler.DeleteTranslation)
+
+			posts.POST("/:id/preview-links", authMiddleware(), previewHandler.CreatePreviewLink)
+			posts.DELETE("/:id/preview-links", authMiddleware(), previewHandler.RevokePreviewLinks)
+
+			posts.GET("/:id/gallery", optionalAuthMiddleware(), galleryHandler.GetPostGallery)
+			posts.POST("/:id/gallery", authMiddleware(), galleryHandler.AddPostImage)
+			posts.PUT("/:id/gallery/order", authMiddleware(), galleryHandler.ReorderPostGallery)
+
+			posts.GET("/:id/reactions", optionalAuthMiddleware(), reactionHandler.GetReactions)
+			posts.PUT("/:id/reactions/:type", authMiddleware(), reactionHandler.AddReaction)
+			posts.DELETE("/:id/reactions/:type", authMiddleware(), reactionHandler.RemoveReaction)
+
+			posts.GET("/:id/tracks", optionalAuthMiddleware(), trackHandler.GetPostTracks)
+			posts.POST("/:id/tracks", authMiddleware(), trackHandler.UploadPostTrack)
+		}
+
+		trips := api.Group("/trips")
+		{
+			trips.POST("", authMiddleware(), tripHandler.CreateTrip)
+			trips.GET("/user/:userId", tripHandler.GetTripsByUser)
+
+			trips.GET("/:id", tripHandler.GetTrip)
+			trips.PUT("/:id", authMiddleware(), tripHandler.UpdateTrip)
+			trips.DELETE("/:id", authMiddleware(), tripHandler.DeleteTrip)
+			trips.PUT("/:id/stops", authMiddleware(), tripHandler.ReplaceStops)
+			trips.GET("/:id/posts", optionalAuthMiddleware(), postHandler.GetPostsByTrip)
+			trips.GET("/:id/summary", optionalAuthMiddleware(), tripHandler.GetTripSummary)
+			trips.GET("/:id/gallery", optionalAuthMiddleware(), galleryHandler.GetTripGallery)
+			trips.POST("/:id/gallery", authMiddleware(), galleryHandler.AddTripImage)
+			trips.PUT("/:id/gallery/order", authMiddleware(), galleryHandler.ReorderTripGallery)
+			trips.GET("/:id/tracks", trackHandler.GetTripTracks)
+			trips.POST("/:id/tracks", authMiddleware(), trackHandler.UploadTripTrack)
+		}
+
+		places := api.Group("/places")
+		{
+			places.GET("", placeHandler.GetPlaces)
+			places.GET("/autocomplete", placeHandler.Autocomplete)
+			places.GET("/:id", placeHandler.GetPlace)
+			places.GET("/:id/posts", optionalAuthMiddleware(), postHandler.GetPostsByPlace)
 		}
 
 		comments := api.Group("/comments")
 		{
 			comments.GET("/count", commentHandler.GetCommentsCount)
-			comments.GET("/post/:postId", commentHandler.GetComments)
+			comments.GET("/trash", authMiddleware(), trashHandler.GetTrashedComments)
+			comments.GET("/moderation", authMiddleware(), commentHandler.GetModerationQueue)
+			comments.POST("/moderation", authMiddleware(), commentHandler.ModerateComments)
+			comments.GET("/post/:postId", optionalAuthMiddleware(), commentHandler.GetComments)
 			comments.POST("/post/:postId", authMiddleware(), commentHandler.CreateComment)
+			comments.PUT("/:id", authMiddleware(), commentHandler.UpdateComment)
+			comments.DELETE("/:id", authMiddleware(), commentHandler.DeleteComment)
+			comments.GET("/:id/replies", optionalAuthMiddleware(), commentHandler.GetReplies)
+			comments.GET("/:id/history", authMiddleware(), commentHandler.GetCommentHistory)
+			comments.POST("/:id/restore", authMiddleware(), trashHandler.RestoreComment)
+			comments.DELETE("/:id/purge", authMiddleware(), trashHandler.PurgeComment)
+		}
+
+		mediaGroup := api.Group("/media")
+		{
+			mediaGroup.POST("", authMiddleware(), mediaHandler.Upload)
+			mediaGroup.GET("", authMiddleware(), mediaHandler.GetMyMedia)
+			mediaGroup.GET("/:id", optionalAuthMiddleware(), mediaHandler.GetMedia)
+			mediaGroup.DELETE("/:id", authMiddleware(), mediaHandler.DeleteMedia)
+		}
+
+		gallery := api.Group("/gallery")
+		{
+			gallery.PUT("/:id", authMiddleware(), galleryHandler.UpdateImage)
+			gallery.DELETE("/:id", authMiddleware(), galleryHandler.DeleteImage)
+		}
+
+		tracks := api.Group("/tracks")
+		{
+			tracks.GET("/:id", optionalAuthMiddleware(), trackHandler.GetTrack)
+			tracks.GET("/:id/geojson", optionalAuthMiddleware(), trackHandler.GetTrackGeoJSON)
+			tracks.DELETE("/:id", authMiddleware(), trackHandler.DeleteTrack)
+		}
+
+		series := api.Group("/series")
+		{
+			series.POST("", authMiddleware(), seriesHandler.CreateSeries)
+			series.GET("/:id", optionalAuthMiddleware(), seriesHandler.GetSeries)
+			series.PUT("/:id", authMiddleware(), seriesHandler.UpdateSeries)
+			series.DELETE("/:id", authMiddleware(), seriesHandler.DeleteSeries)
+			series.POST("/:id/posts", authMiddleware(), seriesHandler.AddPost)
+			series.DELETE("/:id/posts/:postId", authMiddleware(), seriesHandler.RemovePost)
+			series.PUT("/:id/order", authMiddleware(), seriesHandler.ReorderPosts)
+		}
+
+		bookmarks := api.Group("/bookmarks", authMiddleware())
+		{
+			bookmarks.GET("", bookmarkHandler.GetBookmarks)
+			bookmarks.PUT("/:postId", bookmarkHandler.AddBookmark)
+			bookmarks.DELETE("/:postId", bookmarkHandler.RemoveBookmark)
+			bookmarks.GET("/collections", bookmarkHandler.GetCollections)
+			bookmarks.POST("/collections", bookmarkHandler.CreateCollection)
+			bookmarks.PUT("/collections/:id", bookmarkHandler.UpdateCollection)
+			bookmarks.DELETE("/collections/:id", bookmarkHandler.DeleteCollection)
 		}
 
 		users := api.Group("/users")
 		{
 			users.GET("/count", userHandler.GetUsersCount)
+			users.PUT("/me/avatar", authMiddleware(), userHandler.SetAvatar)
+			users.GET("/:id/followers", followHandler.GetFollowers)
+			users.GET("/:id/following", followHandler.GetFollowing)
+			users.GET("/:id/series", seriesHandler.GetSeriesByUser)
+			users.PUT("/:id/follow", authMiddleware(), followHandler.Follow)
+			users.DELETE("/:id/follow", authMiddleware(), followHandler.Unfollow)
+			users.DELETE("/:id/suspension", authMiddleware(), reportHandler.LiftSuspension)
+		}
+
+		reports := api.Group("/reports", authMiddleware())
+		{
+			reports.POST("", reportHandler.CreateReport)
+			reports.GET("", reportHandler.GetReports)
+			reports.POST("/:id/resolve", reportHandler.ResolveReport)
+		}
+
+		notifications := api.Group("/notifications", authMiddleware())
+		{
+			notifications.GET("", notificationHandler.GetNotifications)
+			notifications.POST("/read", notificationHandler.MarkAllRead)
+			notifications.POST("/:id/read", notificationHandler.MarkRead)
 		}
 	}
 
 	return router
 }
 
+// activeUser loads the account a token was issued for. Tokens outlive a
+// suspension, so it is checked on every request: active is false when the
+// account is suspended or, with a zero user ID, no longer exists.
+func activeUser(claims *utils.Claims) (user models.User, active bool, err error) {
+	if err := database.DB.Select("id", "suspended_at", "suspended_until").First(&user, claims.UserID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.User{}, false, nil
+		}
+		return models.User{}, false, err
+	}
+	return user, !user.Suspended(time.Now()), nil
+}
+
 func authMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
@@ -82,6 +272,22 @@ func authMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		user, active, err := activeUser(claims)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to check account"))
+			c.Abort()
+			return
+		}
+		if !active {
+			if user.ID == 0 {
+				c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Invalid or expired token"))
+			} else {
+				c.JSON(http.StatusForbidden, utils.ErrorResponse("account suspended"))
+			}
+			c.Abort()
+			return
+		}
+
 		c.Set("userID", claims.UserID)
 		c.Set("userEmail", claims.Email)
 		c.Set("userRole", claims.Role)
@@ -89,6 +295,32 @@ func authMiddleware() gin.HandlerFunc {
 	}
 }
 
+// optionalAuthMiddleware identifies the reader when a valid token is sent but
+// lets anonymous requests through unchanged.
+func optionalAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parts := strings.Split(c.GetHeader("Authorization"), " ")
+		if len(parts) == 2 && parts[0] == "Bearer" {
+			if claims, err := utils.ValidateToken(parts[1]); err == nil {
+				// Suspended users read like anonymous visitors, so they keep
+				// no more access than anyone else.
+				_, active, err := activeUser(claims)
+				if err != nil {
+					c.JSON(http.StatusInternalServerError, utils.ErrorResponse("failed to check account"))
+					c.Abort()
+					return
+				}
+				if active {
+					c.Set("userID", claims.UserID)
+					c.Set("userEmail", claims.Email)
+					c.Set("userRole", claims.Role)
+				}
+			}
+		}
+		c.Next()
+	}
+}
+
 func corsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
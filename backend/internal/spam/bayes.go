@@ -0,0 +1,139 @@
+package spam
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"travel-blog-backend/internal/database"
+	"travel-blog-backend/internal/models"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+// Training classes of the classifier.
+const (
+	ClassSpam = "spam"
+	ClassHam  = "ham"
+)
+
+// maxTokens bounds the number of distinct tokens taken from one comment,
+// and maxTokenLength the length of each so it fits spam_tokens.token.
+const (
+	maxTokens      = 200
+	maxTokenLength = 100
+)
+
+// Tokenize splits text into the distinct lowercased words the classifier
+// learns from, plus a "domain:" token for every linked host.
+func Tokenize(text string) []string {
+	seen := make(map[string]bool)
+	tokens := make([]string, 0)
+	add := func(token string) {
+		if !seen[token] && len(token) <= maxTokenLength && len(tokens) < maxTokens {
+			seen[token] = true
+			tokens = append(tokens, token)
+		}
+	}
+
+	for _, host := range linkHosts(text) {
+		add("domain:" + host)
+	}
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if n := len(word); n >= 3 && n <= 30 {
+			add(word)
+		}
+	}
+	return tokens
+}
+
+// Classifier is a naive Bayes spam classifier trained on moderators'
+// decisions. Until it has seen MinDocuments comments of each class it
+// abstains.
+type Classifier struct {
+	MinDocuments int
+}
+
+func (*Classifier) Name() string { return "classifier" }
+
+// Score turns the probability that the comment is spam into up to 5 points
+// once that probability is above even.
+func (cl *Classifier) Score(ctx context.Context, comment Comment) (float64, string, error) {
+	probability, trained, err := cl.Probability(ctx, comment.Content)
+	if err != nil || !trained || probability <= 0.5 {
+		return 0, "", err
+	}
+	return 10 * (probability - 0.5), fmt.Sprintf("classifier rates it %.0f%% likely spam", probability*100), nil
+}
+
+// Probability returns the probability that text is spam, and whether the
+// classifier has been trained enough to tell.
+func (cl *Classifier) Probability(ctx context.Context, text string) (float64, bool, error) {
+	db := database.DB.WithContext(ctx)
+
+	var corpora []models.SpamCorpus
+	if err := db.Find(&corpora).Error; err != nil {
+		return 0, false, err
+	}
+	documents := map[string]int64{}
+	for _, corpus := range corpora {
+		documents[corpus.Class] = corpus.Documents
+	}
+	spamDocs, hamDocs := documents[ClassSpam], documents[ClassHam]
+	if spamDocs < int64(cl.MinDocuments) || hamDocs < int64(cl.MinDocuments) {
+		return 0, false, nil
+	}
+
+	tokens := Tokenize(text)
+	if len(tokens) == 0 {
+		return 0, true, nil
+	}
+	var counts []models.SpamToken
+	if err := db.Where("token IN ?", tokens).Find(&counts).Error; err != nil {
+		return 0, false, err
+	}
+
+	// Log odds of spam with add-one smoothing. Tokens never seen in
+	// training carry no evidence either way and are skipped.
+	total := float64(spamDocs + hamDocs)
+	logOdds := math.Log(float64(spamDocs)/total) - math.Log(float64(hamDocs)/total)
+	for _, count := range counts {
+		pSpam := (float64(count.Spam) + 1) / (float64(spamDocs) + 2)
+		pHam := (float64(count.Ham) + 1) / (float64(hamDocs) + 2)
+		logOdds += math.Log(pSpam) - math.Log(pHam)
+	}
+	return 1 / (1 + math.Exp(-logOdds)), true, nil
+}
+
+// Learn adds text to the training data of class, or with delta -1 takes it
+// back out, as when a moderator reverses a decision.
+func Learn(tx *gorm.DB, text, class string, delta int) error {
+	column := "ham"
+	if class == ClassSpam {
+		column = "spam"
+	}
+
+	if err := tx.Exec(`INSERT INTO spam_corpora (class, documents) VALUES (?, GREATEST(?, 0))
+		ON CONFLICT (class) DO UPDATE SET documents = GREATEST(spam_corpora.documents + ?, 0)`,
+		class, delta, delta).Error; err != nil {
+		return err
+	}
+
+	tokens := Tokenize(text)
+	if len(tokens) == 0 {
+		return nil
+	}
+	rows := make([]string, len(tokens))
+	args := make([]interface{}, 0, len(tokens)*2)
+	for i, token := range tokens {
+		rows[i] = "(?, GREATEST(?, 0))"
+		args = append(args, token, delta)
+	}
+	args = append(args, delta)
+	return tx.Exec(fmt.Sprintf(`INSERT INTO spam_tokens (token, %[1]s) VALUES %[2]s
+		ON CONFLICT (token) DO UPDATE SET %[1]s = GREATEST(spam_tokens.%[1]s + ?, 0)`,
+		column, strings.Join(rows, ", ")), args...).Error
+}
@@ -0,0 +1,144 @@
+package spam
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"travel-blog-backend/internal/database"
+	"travel-blog-backend/internal/models"
+)
+
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>"'()\[\]]+`)
+
+// linkHosts returns the lowercased host of every link in text, without a
+// leading "www.".
+func linkHosts(text string) []string {
+	links := linkPattern.FindAllString(text, -1)
+	hosts := make([]string, 0, len(links))
+	for _, link := range links {
+		if !strings.Contains(link, "://") {
+			link = "http://" + link
+		}
+		parsed, err := url.Parse(link)
+		if err != nil || parsed.Hostname() == "" {
+			continue
+		}
+		hosts = append(hosts, strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www."))
+	}
+	return hosts
+}
+
+// LinkCheck scores a point per link, and more once a comment has over
+// MaxLinks links.
+type LinkCheck struct {
+	MaxLinks int
+}
+
+func (LinkCheck) Name() string { return "links" }
+
+func (l LinkCheck) Score(ctx context.Context, comment Comment) (float64, string, error) {
+	links := len(linkHosts(comment.Content))
+	if links == 0 {
+		return 0, "", nil
+	}
+	score := float64(links)
+	if links > l.MaxLinks {
+		score += 3
+	}
+	return score, fmt.Sprintf("contains %d links", links), nil
+}
+
+// BlocklistCheck rejects comments linking to a blocked domain or any of its
+// subdomains.
+type BlocklistCheck struct {
+	domains map[string]bool
+}
+
+func NewBlocklistCheck(domains []string) BlocklistCheck {
+	check := BlocklistCheck{domains: make(map[string]bool, len(domains))}
+	for _, domain := range domains {
+		domain = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(domain)), "www.")
+		if domain != "" {
+			check.domains[domain] = true
+		}
+	}
+	return check
+}
+
+func (BlocklistCheck) Name() string { return "blocklist" }
+
+func (b BlocklistCheck) Score(ctx context.Context, comment Comment) (float64, string, error) {
+	for _, host := range linkHosts(comment.Content) {
+		for domain := host; domain != ""; {
+			if b.domains[domain] {
+				return 10, "links to blocked domain " + domain, nil
+			}
+			_, parent, found := strings.Cut(domain, ".")
+			if !found {
+				break
+			}
+			domain = parent
+		}
+	}
+	return 0, "", nil
+}
+
+// RepeatCheck flags content that the same user, or several users, already
+// posted within Window.
+type RepeatCheck struct {
+	Window time.Duration
+}
+
+func (RepeatCheck) Name() string { return "repeat" }
+
+func (r RepeatCheck) Score(ctx context.Context, comment Comment) (float64, string, error) {
+	content := strings.TrimSpace(comment.Content)
+	if len(content) < 20 {
+		return 0, "", nil
+	}
+
+	var repeats []struct {
+		UserID uint
+		Count  int64
+	}
+	if err := database.DB.WithContext(ctx).Unscoped().Model(&models.Comment{}).
+		Select("user_id, COUNT(*) AS count").
+		Where("created_at >= ? AND TRIM(content) = ?", comment.SubmittedAt.Add(-r.Window), content).
+		Group("user_id").
+		Scan(&repeats).Error; err != nil {
+		return 0, "", err
+	}
+
+	var own, total int64
+	for _, repeat := range repeats {
+		if repeat.UserID == comment.UserID {
+			own = repeat.Count
+		}
+		total += repeat.Count
+	}
+	switch {
+	case total >= 3:
+		return 5, fmt.Sprintf("same content posted %d times recently", total), nil
+	case own > 0:
+		return 4, "repeats one of the user's recent comments", nil
+	}
+	return 0, "", nil
+}
+
+// AccountAgeCheck treats comments from brand new accounts with suspicion.
+type AccountAgeCheck struct{}
+
+func (AccountAgeCheck) Name() string { return "account_age" }
+
+func (AccountAgeCheck) Score(ctx context.Context, comment Comment) (float64, string, error) {
+	switch {
+	case comment.AccountAge < time.Hour:
+		return 3, "account is less than an hour old", nil
+	case comment.AccountAge < 24*time.Hour:
+		return 1.5, "account is less than a day old", nil
+	}
+	return 0, "", nil
+}
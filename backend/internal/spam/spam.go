@@ -0,0 +1,107 @@
+package spam
+
+import (
+	"context"
+	"sort"
+	"time"
+	"travel-blog-backend/internal/config"
+)
+
+// Comment is what the checks know about a new comment.
+type Comment struct {
+	UserID      uint
+	PostID      uint
+	Content     string
+	AccountAge  time.Duration
+	SubmittedAt time.Time
+}
+
+// Signal is the contribution of one check to a comment's spam score.
+type Signal struct {
+	Check  string  `json:"check"`
+	Score  float64 `json:"score"`
+	Reason string  `json:"reason"`
+}
+
+// Check scores one aspect of a comment. A score of zero means the check
+// found nothing suspicious; higher scores are more suspicious.
+type Check interface {
+	Name() string
+	Score(ctx context.Context, comment Comment) (float64, string, error)
+}
+
+type Verdict string
+
+const (
+	VerdictHam    Verdict = "ham"
+	VerdictHold   Verdict = "hold"
+	VerdictReject Verdict = "reject"
+)
+
+// Result is the outcome of running a comment through a Pipeline.
+type Result struct {
+	Score   float64
+	Signals []Signal
+	Verdict Verdict
+}
+
+// Reasons returns the reasons of the signals, strongest first.
+func (r Result) Reasons() []string {
+	reasons := make([]string, len(r.Signals))
+	for i, signal := range r.Signals {
+		reasons[i] = signal.Reason
+	}
+	return reasons
+}
+
+// Pipeline adds up the scores of its checks and compares the total with
+// its thresholds: comments scoring at least Hold are held for moderation,
+// and those scoring at least Reject are refused.
+type Pipeline struct {
+	Checks []Check
+	Hold   float64
+	Reject float64
+}
+
+// New builds the default pipeline from the configuration: the heuristic
+// checks followed by the naive Bayes classifier.
+func New(cfg *config.Config) *Pipeline {
+	return &Pipeline{
+		Checks: []Check{
+			LinkCheck{MaxLinks: cfg.SpamMaxLinks},
+			NewBlocklistCheck(cfg.SpamBlockedDomains),
+			RepeatCheck{Window: 24 * time.Hour},
+			AccountAgeCheck{},
+			&Classifier{MinDocuments: cfg.SpamMinTraining},
+		},
+		Hold:   float64(cfg.SpamHoldScore),
+		Reject: float64(cfg.SpamRejectScore),
+	}
+}
+
+// Evaluate runs comment through every check.
+func (p *Pipeline) Evaluate(ctx context.Context, comment Comment) (Result, error) {
+	result := Result{Verdict: VerdictHam}
+	for _, check := range p.Checks {
+		score, reason, err := check.Score(ctx, comment)
+		if err != nil {
+			return Result{}, err
+		}
+		if score <= 0 {
+			continue
+		}
+		result.Score += score
+		result.Signals = append(result.Signals, Signal{Check: check.Name(), Score: score, Reason: reason})
+	}
+	sort.SliceStable(result.Signals, func(i, j int) bool {
+		return result.Signals[i].Score > result.Signals[j].Score
+	})
+
+	switch {
+	case p.Reject > 0 && result.Score >= p.Reject:
+		result.Verdict = VerdictReject
+	case p.Hold > 0 && result.Score >= p.Hold:
+		result.Verdict = VerdictHold
+	}
+	return result, nil
+}
@@ -0,0 +1,198 @@
+package spam
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"empty", "", []string{}},
+		{"lowercased and deduplicated", "Great post, GREAT photos!", []string{"great", "post", "photos"}},
+		{"short words dropped", "a an the sea", []string{"the", "sea"}},
+		{"letters and digits only", "day-2: 40km (by bike)", []string{"day", "40km", "bike"}},
+		{"unicode words", "Schöne Grüße aus Zürich", []string{"schöne", "grüße", "aus", "zürich"}},
+		{
+			name: "links add domain tokens first",
+			text: "Cheap pills at https://WWW.Pills.example/buy and www.other.example",
+			want: []string{
+				"domain:pills.example", "domain:other.example",
+				"cheap", "pills", "https", "www", "example", "buy", "and", "other",
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Tokenize(tc.text); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Tokenize(%q) = %q, want %q", tc.text, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTokenizeLimits(t *testing.T) {
+	var words []string
+	for i := 0; i < maxTokens+50; i++ {
+		words = append(words, "word"+strings.Repeat("x", i%20)+string(rune('a'+i/20)))
+	}
+	words = append(words, strings.Repeat("y", 31))
+
+	tokens := Tokenize(strings.Join(words, " "))
+	if len(tokens) != maxTokens {
+		t.Errorf("got %d tokens, want %d", len(tokens), maxTokens)
+	}
+	for _, token := range tokens {
+		if len(token) > 30 {
+			t.Errorf("token %q is longer than 30 bytes", token)
+		}
+	}
+}
+
+// stubCheck returns a fixed score, reason and error.
+type stubCheck struct {
+	name   string
+	score  float64
+	reason string
+	err    error
+}
+
+func (s stubCheck) Name() string { return s.name }
+
+func (s stubCheck) Score(ctx context.Context, comment Comment) (float64, string, error) {
+	return s.score, s.reason, s.err
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name        string
+		checks      []Check
+		hold        float64
+		reject      float64
+		wantScore   float64
+		wantVerdict Verdict
+		wantReasons []string
+	}{
+		{
+			name:        "no checks",
+			hold:        5,
+			reject:      10,
+			wantVerdict: VerdictHam,
+			wantReasons: []string{},
+		},
+		{
+			name: "below hold",
+			checks: []Check{
+				stubCheck{name: "a", score: 2, reason: "a"},
+				stubCheck{name: "b", score: 0, reason: "ignored"},
+			},
+			hold:        5,
+			reject:      10,
+			wantScore:   2,
+			wantVerdict: VerdictHam,
+			wantReasons: []string{"a"},
+		},
+		{
+			name: "scores add up to hold",
+			checks: []Check{
+				stubCheck{name: "a", score: 2, reason: "weak"},
+				stubCheck{name: "b", score: 3, reason: "strong"},
+			},
+			hold:        5,
+			reject:      10,
+			wantScore:   5,
+			wantVerdict: VerdictHold,
+			wantReasons: []string{"strong", "weak"},
+		},
+		{
+			name: "reject wins over hold",
+			checks: []Check{
+				stubCheck{name: "a", score: 10, reason: "blocked"},
+			},
+			hold:        5,
+			reject:      10,
+			wantScore:   10,
+			wantVerdict: VerdictReject,
+			wantReasons: []string{"blocked"},
+		},
+		{
+			name: "negative scores are ignored",
+			checks: []Check{
+				stubCheck{name: "a", score: -4, reason: "trusted"},
+				stubCheck{name: "b", score: 5, reason: "suspicious"},
+			},
+			hold:        5,
+			reject:      10,
+			wantScore:   5,
+			wantVerdict: VerdictHold,
+			wantReasons: []string{"suspicious"},
+		},
+		{
+			name: "zero thresholds are disabled",
+			checks: []Check{
+				stubCheck{name: "a", score: 100, reason: "a"},
+			},
+			wantScore:   100,
+			wantVerdict: VerdictHam,
+			wantReasons: []string{"a"},
+		},
+		{
+			name: "heuristics without the database",
+			checks: []Check{
+				LinkCheck{MaxLinks: 1},
+				NewBlocklistCheck([]string{" WWW.Spam.Example "}),
+				AccountAgeCheck{},
+			},
+			hold:        5,
+			reject:      10,
+			wantScore:   2 + 3 + 10 + 3,
+			wantVerdict: VerdictReject,
+			wantReasons: []string{
+				"links to blocked domain spam.example",
+				"contains 2 links",
+				"account is less than an hour old",
+			},
+		},
+	}
+
+	comment := Comment{
+		Content:    "Visit https://shop.spam.example and https://example.org",
+		AccountAge: time.Minute,
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pipeline := &Pipeline{Checks: tc.checks, Hold: tc.hold, Reject: tc.reject}
+			result, err := pipeline.Evaluate(context.Background(), comment)
+			if err != nil {
+				t.Fatalf("Evaluate: %v", err)
+			}
+			if result.Score != tc.wantScore || result.Verdict != tc.wantVerdict {
+				t.Errorf("got score %v verdict %q, want %v %q", result.Score, result.Verdict, tc.wantScore, tc.wantVerdict)
+			}
+			if reasons := result.Reasons(); !reflect.DeepEqual(reasons, tc.wantReasons) {
+				t.Errorf("Reasons() = %q, want %q", reasons, tc.wantReasons)
+			}
+		})
+	}
+}
+
+func TestEvaluateError(t *testing.T) {
+	failure := errors.New("lookup failed")
+	pipeline := &Pipeline{
+		Checks: []Check{
+			stubCheck{name: "a", score: 3, reason: "a"},
+			stubCheck{name: "b", err: failure},
+		},
+		Hold: 1,
+	}
+	if _, err := pipeline.Evaluate(context.Background(), Comment{}); !errors.Is(err, failure) {
+		t.Errorf("Evaluate error = %v, want %v", err, failure)
+	}
+}
@@ -0,0 +1,35 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"travel-blog-backend/internal/config"
+)
+
+// Storage persists uploaded files under slash-separated keys such as
+// "media/2026/10/abc.jpg" and knows the public URL of each stored object.
+type Storage interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+var ErrInvalidKey = errors.New("invalid storage key")
+
+func New(cfg *config.Config) (Storage, error) {
+	switch cfg.StorageDriver {
+	case "local":
+		return NewLocalStorage(cfg.UploadsDir, cfg.MediaBaseURL), nil
+	case "s3":
+		return NewS3Storage(S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			PublicURL: cfg.S3PublicURL,
+		})
+	}
+	return nil, fmt.Errorf("unknown storage driver %q", cfg.StorageDriver)
+}
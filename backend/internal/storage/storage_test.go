@@ -0,0 +1,240 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fetchFunc downloads a stored object by its public URL.
+type fetchFunc func(url string) (*http.Response, error)
+
+// testContract checks the behaviour every Storage implementation must share:
+// stored objects are served from their URL, can be replaced and deleted,
+// deleting a missing key is not an error, and unsafe keys are rejected.
+func testContract(t *testing.T, store Storage, fetch fetchFunc) {
+	ctx := context.Background()
+	key := "media/2026/10/" + strings.ReplaceAll(t.Name(), "/", "-") + ".jpg"
+
+	get := func(t *testing.T) (int, string, string) {
+		t.Helper()
+		resp, err := fetch(store.URL(key))
+		if err != nil {
+			t.Fatalf("fetch %s: %v", store.URL(key), err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("read %s: %v", store.URL(key), err)
+		}
+		return resp.StatusCode, resp.Header.Get("Content-Type"), string(body)
+	}
+
+	t.Run("put", func(t *testing.T) {
+		if err := store.Put(ctx, key, []byte("first"), "image/jpeg"); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+		status, contentType, body := get(t)
+		if status != http.StatusOK || body != "first" {
+			t.Fatalf("got %d %q, want 200 %q", status, body, "first")
+		}
+		if contentType != "image/jpeg" {
+			t.Errorf("Content-Type = %q, want image/jpeg", contentType)
+		}
+	})
+
+	t.Run("overwrite", func(t *testing.T) {
+		if err := store.Put(ctx, key, []byte("second"), "image/jpeg"); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+		if status, _, body := get(t); status != http.StatusOK || body != "second" {
+			t.Fatalf("got %d %q, want 200 %q", status, body, "second")
+		}
+	})
+
+	t.Run("url", func(t *testing.T) {
+		if url := store.URL(key); !strings.HasSuffix(url, "/"+key) {
+			t.Errorf("URL(%q) = %q, want it to end with the key", key, url)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		if err := store.Delete(ctx, key); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if status, _, _ := get(t); status != http.StatusNotFound {
+			t.Fatalf("got %d after delete, want 404", status)
+		}
+		if err := store.Delete(ctx, key); err != nil {
+			t.Fatalf("Delete of a missing key: %v", err)
+		}
+	})
+
+	t.Run("invalid keys", func(t *testing.T) {
+		for _, bad := range []string{"", "/media/a.jpg", "../a.jpg", "media/../../a.jpg"} {
+			if err := store.Put(ctx, bad, []byte("x"), "image/jpeg"); !errors.Is(err, ErrInvalidKey) {
+				t.Errorf("Put(%q) = %v, want ErrInvalidKey", bad, err)
+			}
+			if err := store.Delete(ctx, bad); !errors.Is(err, ErrInvalidKey) {
+				t.Errorf("Delete(%q) = %v, want ErrInvalidKey", bad, err)
+			}
+		}
+	})
+}
+
+func TestLocalStorage(t *testing.T) {
+	dir := t.TempDir()
+	server := httptest.NewServer(http.StripPrefix("/uploads", http.FileServer(http.Dir(dir))))
+	defer server.Close()
+
+	testContract(t, NewLocalStorage(dir, server.URL+"/uploads/"), http.Get)
+}
+
+func TestS3Storage(t *testing.T) {
+	fake := newFakeS3("test-bucket", "test-key")
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	store, err := NewS3Storage(S3Config{
+		Endpoint:  server.URL,
+		Bucket:    "test-bucket",
+		AccessKey: "test-key",
+		SecretKey: "test-secret",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testContract(t, store, http.Get)
+
+	t.Run("errors", func(t *testing.T) {
+		denied, err := NewS3Storage(S3Config{
+			Endpoint:  server.URL,
+			Bucket:    "test-bucket",
+			AccessKey: "other-key",
+			SecretKey: "test-secret",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := denied.Put(context.Background(), "media/a.jpg", []byte("x"), "image/jpeg"); err == nil {
+			t.Fatal("Put with rejected credentials succeeded")
+		}
+	})
+}
+
+// TestS3StorageMinIO runs the contract against a real S3-compatible server,
+// e.g. `docker run -p 9000:9000 minio/minio server /data` with a bucket
+// created up front. It is skipped unless S3_TEST_ENDPOINT is set.
+func TestS3StorageMinIO(t *testing.T) {
+	endpoint := os.Getenv("S3_TEST_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("S3_TEST_ENDPOINT not set")
+	}
+
+	store, err := NewS3Storage(S3Config{
+		Endpoint:  endpoint,
+		Region:    os.Getenv("S3_TEST_REGION"),
+		Bucket:    envOr("S3_TEST_BUCKET", "travel-blog-test"),
+		AccessKey: envOr("S3_TEST_ACCESS_KEY", "minioadmin"),
+		SecretKey: envOr("S3_TEST_SECRET_KEY", "minioadmin"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Test buckets are usually private, so read objects back with signed
+	// requests instead of relying on a public bucket policy.
+	fetch := func(url string) (*http.Response, error) {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		store.sign(req, nil, time.Now().UTC())
+		return store.client.Do(req)
+	}
+	testContract(t, store, fetch)
+}
+
+func envOr(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
+
+type fakeObject struct {
+	data        []byte
+	contentType string
+}
+
+// fakeS3 is an in-memory, path-style S3 endpoint for a single bucket. Writes
+// must carry a SigV4 Authorization header for accessKey and a payload hash
+// matching the body; reads are public.
+type fakeS3 struct {
+	bucket    string
+	accessKey string
+
+	mu      sync.Mutex
+	objects map[string]fakeObject
+}
+
+func newFakeS3(bucket, accessKey string) *fakeS3 {
+	return &fakeS3{bucket: bucket, accessKey: accessKey, objects: make(map[string]fakeObject)}
+}
+
+func (s *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key, ok := strings.CutPrefix(r.URL.Path, "/"+s.bucket+"/")
+	if !ok || key == "" {
+		http.Error(w, "NoSuchBucket", http.StatusNotFound)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential="+s.accessKey+"/") {
+			http.Error(w, "AccessDenied", http.StatusForbidden)
+			return
+		}
+		sum := sha256.Sum256(body)
+		if r.Header.Get("X-Amz-Content-Sha256") != hex.EncodeToString(sum[:]) {
+			http.Error(w, "XAmzContentSHA256Mismatch", http.StatusBadRequest)
+			return
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		s.objects[key] = fakeObject{data: body, contentType: r.Header.Get("Content-Type")}
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet:
+		object, ok := s.objects[key]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", object.contentType)
+		w.Write(object.data)
+	case http.MethodDelete:
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "MethodNotAllowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	createdAt := time.Date(2026, 10, 18, 9, 30, 15, 123456789, time.UTC)
+	for _, cursor := range []Cursor{
+		{CreatedAt: createdAt, ID: 42},
+		{CreatedAt: createdAt, ID: 7, Backward: true},
+	} {
+		encoded := EncodeCursor(cursor)
+		decoded, err := DecodeCursor(encoded)
+		if err != nil {
+			t.Fatalf("DecodeCursor(%q): %v", encoded, err)
+		}
+		// Nanoseconds must survive so rows created in the same second are
+		// neither skipped nor repeated between pages.
+		if !decoded.CreatedAt.Equal(cursor.CreatedAt) || decoded.ID != cursor.ID || decoded.Backward != cursor.Backward {
+			t.Errorf("round trip of %+v = %+v", cursor, decoded)
+		}
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"empty", ""},
+		{"not base64", "%%%"},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte(`{"t":"2026-10-18T09:30:15Z","id":1}`))},
+		{"not json", encode("cursor")},
+		{"missing id", encode(`{"t":"2026-10-18T09:30:15Z"}`)},
+		{"zero id", encode(`{"t":"2026-10-18T09:30:15Z","id":0}`)},
+		{"negative id", encode(`{"t":"2026-10-18T09:30:15Z","id":-1}`)},
+		{"bad time", encode(`{"t":"yesterday","id":1}`)},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := DecodeCursor(tc.value); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("DecodeCursor(%q) = %v, want ErrInvalidCursor", tc.value, err)
+			}
+		})
+	}
+}
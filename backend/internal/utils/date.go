@@ -0,0 +1,19 @@
+package utils
+
+import "time"
+
+const DateLayout = "2006-01-02"
+
+func ParseDate(value string) (time.Time, error) {
+	return time.Parse(DateLayout, value)
+}
+
+// DaysBetween returns the number of calendar days covered by the inclusive
+// range [start, end].
+func DaysBetween(start, end time.Time) int {
+	days := int(end.Sub(start).Hours()/24) + 1
+	if days < 0 {
+		return 0
+	}
+	return days
+}
@@ -0,0 +1,216 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+type diffOp struct {
+	kind byte
+	text string
+}
+
+// UnifiedDiff returns a line-based diff of a and b in unified format with the
+// given number of context lines. It returns an empty string when the inputs
+// are equal.
+func UnifiedDiff(fromName, toName, a, b string, context int) string {
+	if a == b {
+		return ""
+	}
+
+	ops := diffLines(splitLines(a), splitLines(b))
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+
+	// Walk the edit script, emitting one hunk for every run of changes that
+	// are no more than 2*context unchanged lines apart.
+	aLine, bLine := 1, 1
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			aLine++
+			bLine++
+			i++
+			continue
+		}
+
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			run := end
+			for run < len(ops) && ops[run].kind == ' ' {
+				run++
+			}
+			if run == len(ops) || run-end > 2*context {
+				end += min(context, run-end)
+				break
+			}
+			end = run
+		}
+
+		hunkA, hunkB := aLine-(i-start), bLine-(i-start)
+		var countA, countB int
+		var body strings.Builder
+		for _, op := range ops[start:end] {
+			switch op.kind {
+			case ' ':
+				countA++
+				countB++
+			case '-':
+				countA++
+			case '+':
+				countB++
+			}
+			body.WriteByte(op.kind)
+			body.WriteString(op.text)
+			body.WriteByte('\n')
+		}
+
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(hunkA, countA), hunkRange(hunkB, countB))
+		out.WriteString(body.String())
+
+		for _, op := range ops[i:end] {
+			if op.kind != '+' {
+				aLine++
+			}
+			if op.kind != '-' {
+				bLine++
+			}
+		}
+		i = end
+	}
+
+	return out.String()
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start-1)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffLines computes a shortest edit script between a and b using Myers'
+// linear-space algorithm, so memory stays proportional to the input size
+// rather than the product of the two line counts.
+func diffLines(a, b []string) []diffOp {
+	ops := make([]diffOp, 0, len(a)+len(b))
+	return appendDiff(ops, a, b)
+}
+
+func appendDiff(ops []diffOp, a, b []string) []diffOp {
+	// Stripping the common prefix and suffix at every level keeps typical
+	// edits cheap and guarantees each split below makes progress.
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{' ', line})
+	}
+
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	switch {
+	case len(midA) == 0:
+		for _, line := range midB {
+			ops = append(ops, diffOp{'+', line})
+		}
+	case len(midB) == 0:
+		for _, line := range midA {
+			ops = append(ops, diffOp{'-', line})
+		}
+	default:
+		x, y, u, v := middleSnake(midA, midB)
+		ops = appendDiff(ops, midA[:x], midB[:y])
+		for _, line := range midA[x:u] {
+			ops = append(ops, diffOp{' ', line})
+		}
+		ops = appendDiff(ops, midA[u:], midB[v:])
+	}
+
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	return ops
+}
+
+// middleSnake finds the middle snake of a shortest edit script between a and
+// b by searching forwards from the start and backwards from the end until the
+// two paths overlap. It returns the snake as the points (x, y) and (u, v).
+func middleSnake(a, b []string) (x, y, u, v int) {
+	n, m := len(a), len(b)
+	delta := n - m
+	odd := delta%2 != 0
+	limit := (n + m + 1) / 2
+	offset := limit + 1
+	forward := make([]int, 2*limit+3)
+	backward := make([]int, 2*limit+3)
+
+	for d := 0; d <= limit; d++ {
+		for k := -d; k <= d; k += 2 {
+			if k == -d || (k != d && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+			y = x - k
+			u, v = x, y
+			for u < n && v < m && a[u] == b[v] {
+				u++
+				v++
+			}
+			forward[offset+k] = u
+
+			if c := delta - k; odd && c >= -(d-1) && c <= d-1 && u+backward[offset+c] >= n {
+				return x, y, u, v
+			}
+		}
+
+		// The backward search runs over the reversed inputs; diagonal c there
+		// corresponds to diagonal delta-c going forwards.
+		for c := -d; c <= d; c += 2 {
+			var rx int
+			if c == -d || (c != d && backward[offset+c-1] < backward[offset+c+1]) {
+				rx = backward[offset+c+1]
+			} else {
+				rx = backward[offset+c-1] + 1
+			}
+			ry := rx - c
+			sx, sy := rx, ry
+			for rx < n && ry < m && a[n-1-rx] == b[m-1-ry] {
+				rx++
+				ry++
+			}
+			backward[offset+c] = rx
+
+			if k := delta - c; !odd && k >= -d && k <= d && forward[offset+k]+rx >= n {
+				return n - rx, m - ry, n - sx, m - sy
+			}
+		}
+	}
+
+	// Unreachable: the searches always meet within limit steps.
+	return 0, 0, 0, 0
+}
@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    string
+		context int
+		want    string
+	}{
+		{
+			name: "equal",
+			a:    "one\ntwo\n",
+			b:    "one\ntwo\n",
+			want: "",
+		},
+		{
+			name:    "changed line",
+			a:       "one\ntwo\nthree\n",
+			b:       "one\n2\nthree\n",
+			context: 1,
+			want:    "--- a\n+++ b\n@@ -1,3 +1,3 @@\n one\n-two\n+2\n three\n",
+		},
+		{
+			name:    "from empty",
+			a:       "",
+			b:       "one\ntwo\n",
+			context: 3,
+			want:    "--- a\n+++ b\n@@ -0,0 +1,2 @@\n+one\n+two\n",
+		},
+		{
+			name:    "to empty",
+			a:       "one\n",
+			b:       "",
+			context: 3,
+			want:    "--- a\n+++ b\n@@ -1 +0,0 @@\n-one\n",
+		},
+		{
+			name:    "distant changes get separate hunks",
+			a:       "1\n2\n3\n4\n5\n6\n7\n8\n",
+			b:       "x\n2\n3\n4\n5\n6\n7\ny\n",
+			context: 1,
+			want:    "--- a\n+++ b\n@@ -1,2 +1,2 @@\n-1\n+x\n 2\n@@ -7,2 +7,2 @@\n 7\n-8\n+y\n",
+		},
+		{
+			name:    "nearby changes share a hunk",
+			a:       "1\n2\n3\n4\n5\n",
+			b:       "x\n2\n3\n4\ny\n",
+			context: 2,
+			want:    "--- a\n+++ b\n@@ -1,5 +1,5 @@\n-1\n+x\n 2\n 3\n 4\n-5\n+y\n",
+		},
+		{
+			name:    "no context",
+			a:       "1\n2\n3\n",
+			b:       "1\n3\n",
+			context: 0,
+			want:    "--- a\n+++ b\n@@ -2 +1,0 @@\n-2\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := UnifiedDiff("a", "b", tc.a, tc.b, tc.context); got != tc.want {
+				t.Errorf("UnifiedDiff() =\n%s\nwant\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+// TestDiffLinesMinimal checks on random inputs that the edit script rebuilds
+// both sides and keeps as many lines as a longest common subsequence.
+func TestDiffLinesMinimal(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	randomLines := func() []string {
+		lines := make([]string, rng.Intn(30))
+		for i := range lines {
+			lines[i] = string(rune('a' + rng.Intn(4)))
+		}
+		return lines
+	}
+
+	for i := 0; i < 500; i++ {
+		a, b := randomLines(), randomLines()
+		ops := diffLines(a, b)
+
+		var gotA, gotB []string
+		kept := 0
+		for _, op := range ops {
+			if op.kind != '+' {
+				gotA = append(gotA, op.text)
+			}
+			if op.kind != '-' {
+				gotB = append(gotB, op.text)
+			}
+			if op.kind == ' ' {
+				kept++
+			}
+		}
+		if strings.Join(gotA, "\n") != strings.Join(a, "\n") || strings.Join(gotB, "\n") != strings.Join(b, "\n") {
+			t.Fatalf("diffLines(%q, %q) does not rebuild its inputs: %v", a, b, ops)
+		}
+		if want := lcsLength(a, b); kept != want {
+			t.Fatalf("diffLines(%q, %q) keeps %d lines, want %d", a, b, kept, want)
+		}
+	}
+}
+
+func lcsLength(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	for i := range a {
+		cur := make([]int, len(b)+1)
+		for j := range b {
+			if a[i] == b[j] {
+				cur[j+1] = prev[j] + 1
+			} else {
+				cur[j+1] = max(prev[j+1], cur[j])
+			}
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
@@ -8,10 +8,25 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// previewPurpose marks tokens that grant read access to a single post. They
+// are signed with the same secret as session tokens, so each kind of token
+// rejects the other by its purpose.
+const previewPurpose = "post-preview"
+
 type Claims struct {
-	UserID uint   `json:"userId"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
+	UserID  uint   `json:"userId"`
+	Email   string `json:"email"`
+	Role    string `json:"role"`
+	Purpose string `json:"purpose,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// PreviewClaims identify the post a preview link opens. Version must match
+// the post's preview version, so bumping it revokes every link handed out.
+type PreviewClaims struct {
+	PostID  uint   `json:"postId"`
+	Version int    `json:"version"`
+	Purpose string `json:"purpose"`
 	jwt.RegisteredClaims
 }
 
@@ -39,7 +54,44 @@ func GenerateRefreshToken(userID uint, email, role string) (string, error) {
 }
 
 func ValidateToken(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+	claims := &Claims{}
+	if err := parseToken(tokenString, claims); err != nil {
+		return nil, err
+	}
+	if claims.Purpose != "" {
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
+}
+
+func GeneratePreviewToken(postID uint, version int, expiresAt time.Time) (string, error) {
+	claims := PreviewClaims{
+		PostID:  postID,
+		Version: version,
+		Purpose: previewPurpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(config.AppConfig.JWTSecret))
+}
+
+func ValidatePreviewToken(tokenString string) (*PreviewClaims, error) {
+	claims := &PreviewClaims{}
+	if err := parseToken(tokenString, claims); err != nil {
+		return nil, err
+	}
+	if claims.Purpose != previewPurpose || claims.PostID == 0 {
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
+}
+
+func parseToken(tokenString string, claims jwt.Claims) error {
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, errors.New("unexpected signing method")
 		}
@@ -47,13 +99,10 @@ func ValidateToken(tokenString string) (*Claims, error) {
 	})
 	
 	if err != nil {
-		return nil, err
+		return err
 	}
-	
-	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
-		return claims, nil
+	if !token.Valid {
+		return errors.New("invalid token")
 	}
-	
-	return nil, errors.New("invalid token")
+	return nil
 }
-
@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var languageTag = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})*$`)
+
+// NormalizeLanguage lowercases a BCP 47 language tag such as "pt-BR" and
+// reports whether it is well formed. Underscores are accepted as separators.
+func NormalizeLanguage(value string) (string, bool) {
+	tag := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(value), "_", "-"))
+	if len(tag) > 20 || !languageTag.MatchString(tag) {
+		return "", false
+	}
+	return tag, true
+}
+
+// ParseAcceptLanguage returns the languages of an Accept-Language header,
+// most preferred first. Wildcards, malformed tags and languages with q=0
+// are left out.
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+
+	var entries []weighted
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		tag, ok := NormalizeLanguage(fields[0])
+		if !ok {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			name, value, found := strings.Cut(strings.TrimSpace(param), "=")
+			if !found || name != "q" {
+				continue
+			}
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil || parsed < 0 || parsed > 1 {
+				q = 0
+			} else {
+				q = parsed
+			}
+		}
+		if q > 0 {
+			entries = append(entries, weighted{tag, q})
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].q > entries[j].q
+	})
+
+	tags := make([]string, len(entries))
+	for i, entry := range entries {
+		tags[i] = entry.tag
+	}
+	return tags
+}
+
+// MatchLanguage picks the first preferred language that is available. A
+// preference also matches an available language with the same primary
+// subtag, so "pt-br" is served "pt" and the other way round. It returns ""
+// when nothing matches.
+func MatchLanguage(preferred, available []string) string {
+	for _, want := range preferred {
+		for _, have := range available {
+			if want == have {
+				return have
+			}
+		}
+		base := primarySubtag(want)
+		for _, have := range available {
+			if primarySubtag(have) == base {
+				return have
+			}
+		}
+	}
+	return ""
+}
+
+func primarySubtag(tag string) string {
+	base, _, _ := strings.Cut(tag, "-")
+	return base
+}
@@ -0,0 +1,246 @@
+package views
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"travel-blog-backend/internal/database"
+	"travel-blog-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	dayLayout = "2006-01-02"
+
+	// maxPending bounds the memory used by unflushed views. Views recorded
+	// while the buffer is full are dropped.
+	maxPending = 100_000
+
+	// flushBatchSize is the number of views inserted per statement.
+	flushBatchSize = 1000
+)
+
+type view struct {
+	postID  uint
+	day     string
+	visitor string
+}
+
+type postDay struct {
+	postID uint
+	day    string
+}
+
+var (
+	mu      sync.Mutex
+	pending = make(map[view]struct{})
+	dropped int
+
+	saltMu  sync.Mutex
+	saltDay string
+	salt    []byte
+)
+
+var botMarkers = []string{"bot", "crawler", "spider", "slurp", "preview"}
+
+// IsBot reports whether userAgent looks like an automated client whose
+// requests should not count as views.
+func IsBot(userAgent string) bool {
+	ua := strings.ToLower(userAgent)
+	if ua == "" {
+		return true
+	}
+	for _, marker := range botMarkers {
+		if strings.Contains(ua, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// Visitor turns identity (an IP address and user agent, or a user ID) into
+// an opaque ID that is only stable for the current UTC day. It is hashed with
+// a random salt that is shared by all replicas through the database and
+// deleted once the day is over, so stored IDs cannot be linked back to a
+// visitor or across days.
+func Visitor(ctx context.Context, identity string, now time.Time) (string, error) {
+	daySalt, err := currentSalt(ctx, now.UTC().Format(dayLayout))
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	hash.Write(daySalt)
+	hash.Write([]byte(identity))
+	return hex.EncodeToString(hash.Sum(nil)[:16]), nil
+}
+
+func currentSalt(ctx context.Context, day string) ([]byte, error) {
+	saltMu.Lock()
+	defer saltMu.Unlock()
+
+	if saltDay == day {
+		return salt, nil
+	}
+
+	fresh := make([]byte, 32)
+	if _, err := rand.Read(fresh); err != nil {
+		return nil, err
+	}
+
+	// Whichever replica inserts first wins; everyone then reads its salt.
+	db := database.DB.WithContext(ctx)
+	if err := db.Exec("INSERT INTO view_salts (day, salt) VALUES (?, ?) ON CONFLICT (day) DO NOTHING", day, fresh).Error; err != nil {
+		return nil, err
+	}
+	var stored models.ViewSalt
+	if err := db.Where("day = ?", day).First(&stored).Error; err != nil {
+		return nil, err
+	}
+
+	saltDay, salt = day, stored.Salt
+	return salt, nil
+}
+
+// Record buffers a view of postID by visitor. Repeated views by the same
+// visitor on the same day are counted once.
+func Record(postID uint, visitor string, now time.Time) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(pending) >= maxPending {
+		dropped++
+		return
+	}
+	pending[view{postID: postID, day: now.UTC().Format(dayLayout), visitor: visitor}] = struct{}{}
+}
+
+// Flush writes the buffered views to the database. Views already counted for
+// the same visitor and day, by this or another replica, are skipped. On
+// failure the views are put back so the next flush retries them.
+func Flush(ctx context.Context) error {
+	mu.Lock()
+	batch := pending
+	pending = make(map[view]struct{})
+	if dropped > 0 {
+		log.Printf("Dropped %d post views because the buffer was full", dropped)
+		dropped = 0
+	}
+	mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	// Rows are written in a fixed order so concurrent flushes from several
+	// replicas cannot deadlock on each other's locks.
+	views := make([]view, 0, len(batch))
+	for v := range batch {
+		views = append(views, v)
+	}
+	sort.Slice(views, func(i, j int) bool {
+		a, b := views[i], views[j]
+		if a.postID != b.postID {
+			return a.postID < b.postID
+		}
+		if a.day != b.day {
+			return a.day < b.day
+		}
+		return a.visitor < b.visitor
+	})
+
+	err := database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		counts := make(map[postDay]int64)
+		for start := 0; start < len(views); start += flushBatchSize {
+			end := min(start+flushBatchSize, len(views))
+			if err := insertVisitors(tx, views[start:end], counts); err != nil {
+				return err
+			}
+		}
+
+		keys := make([]postDay, 0, len(counts))
+		for key := range counts {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if keys[i].postID != keys[j].postID {
+				return keys[i].postID < keys[j].postID
+			}
+			return keys[i].day < keys[j].day
+		})
+
+		perPost := make(map[uint]int64)
+		postIDs := make([]uint, 0)
+		for _, key := range keys {
+			n := counts[key]
+			if err := tx.Exec(`INSERT INTO post_view_daily (post_id, day, views) VALUES (?, ?::date, ?)
+				ON CONFLICT (post_id, day) DO UPDATE SET views = post_view_daily.views + EXCLUDED.views`,
+				key.postID, key.day, n).Error; err != nil {
+				return err
+			}
+			if perPost[key.postID] == 0 {
+				postIDs = append(postIDs, key.postID)
+			}
+			perPost[key.postID] += n
+		}
+
+		for _, postID := range postIDs {
+			if err := tx.Model(&models.Post{}).
+				Where("id = ?", postID).
+				UpdateColumn("view_count", gorm.Expr("view_count + ?", perPost[postID])).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		mu.Lock()
+		for v := range batch {
+			if len(pending) >= maxPending {
+				break
+			}
+			pending[v] = struct{}{}
+		}
+		mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// insertVisitors stores the first view of each visitor and adds the newly
+// counted views to counts, keyed by post and day. Views of posts deleted in
+// the meantime are ignored.
+func insertVisitors(tx *gorm.DB, views []view, counts map[postDay]int64) error {
+	values := make([]string, len(views))
+	args := make([]interface{}, 0, len(views)*3)
+	for i, v := range views {
+		values[i] = "(?::bigint, ?::date, ?::text)"
+		args = append(args, v.postID, v.day, v.visitor)
+	}
+
+	var inserted []struct {
+		PostID uint
+		Day    string
+	}
+	query := fmt.Sprintf(`INSERT INTO post_view_visitors (post_id, day, visitor)
+		SELECT v.post_id, v.day, v.visitor FROM (VALUES %s) AS v (post_id, day, visitor)
+		WHERE EXISTS (SELECT 1 FROM posts WHERE posts.id = v.post_id)
+		ON CONFLICT DO NOTHING
+		RETURNING post_id, to_char(day, 'YYYY-MM-DD') AS day`, strings.Join(values, ", "))
+	if err := tx.Raw(query, args...).Scan(&inserted).Error; err != nil {
+		return err
+	}
+
+	for _, row := range inserted {
+		counts[postDay{postID: row.PostID, day: row.Day}]++
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS trips (
+  id BIGSERIAL PRIMARY KEY,
+  title TEXT NOT NULL,
+  description TEXT,
+  cover_image_url TEXT,
+  start_date DATE NOT NULL,
+  end_date DATE NOT NULL,
+  user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  CONSTRAINT chk_trips_dates CHECK (end_date >= start_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_trips_user_id ON trips (user_id);
+CREATE INDEX IF NOT EXISTS idx_trips_created_at ON trips (created_at);
+
+CREATE TABLE IF NOT EXISTS trip_stops (
+  id BIGSERIAL PRIMARY KEY,
+  trip_id BIGINT NOT NULL REFERENCES trips (id) ON DELETE CASCADE,
+  position INTEGER NOT NULL,
+  name TEXT NOT NULL,
+  country TEXT,
+  arrival_date DATE NOT NULL,
+  departure_date DATE,
+  notes TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_trip_stops_trip_id ON trip_stops (trip_id, position);
+
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS trip_id BIGINT REFERENCES trips (id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_posts_trip_id ON posts (trip_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_posts_trip_id;
+ALTER TABLE posts DROP COLUMN IF EXISTS trip_id;
+DROP TABLE IF EXISTS trip_stops;
+DROP TABLE IF EXISTS trips;
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS places (
+  id BIGSERIAL PRIMARY KEY,
+  slug TEXT NOT NULL,
+  name TEXT NOT NULL,
+  type VARCHAR(20) NOT NULL,
+  parent_id BIGINT REFERENCES places (id) ON DELETE CASCADE,
+  country_code VARCHAR(2),
+  latitude DOUBLE PRECISION,
+  longitude DOUBLE PRECISION
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_places_slug ON places (slug);
+CREATE INDEX IF NOT EXISTS idx_places_parent_id ON places (parent_id);
+CREATE INDEX IF NOT EXISTS idx_places_name ON places (lower(name) text_pattern_ops);
+
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS place_id BIGINT REFERENCES places (id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_posts_place_id ON posts (place_id);
+
+ALTER TABLE trip_stops ADD COLUMN IF NOT EXISTS place_id BIGINT REFERENCES places (id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_trip_stops_place_id ON trip_stops (place_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE trip_stops DROP COLUMN IF EXISTS place_id;
+ALTER TABLE posts DROP COLUMN IF EXISTS place_id;
+DROP TABLE IF EXISTS places;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS publish_at TIMESTAMPTZ;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS published_at TIMESTAMPTZ;
+
+UPDATE posts SET published_at = created_at WHERE status = 'published' AND published_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_posts_published_at ON posts (published_at);
+CREATE INDEX IF NOT EXISTS idx_posts_scheduled ON posts (publish_at) WHERE status = 'scheduled';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE posts SET status = 'draft' WHERE status = 'scheduled';
+DROP INDEX IF EXISTS idx_posts_scheduled;
+DROP INDEX IF EXISTS idx_posts_published_at;
+ALTER TABLE posts DROP COLUMN IF EXISTS published_at;
+ALTER TABLE posts DROP COLUMN IF EXISTS publish_at;
+-- +goose StatementEnd
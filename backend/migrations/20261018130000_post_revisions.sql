@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS post_revisions (
+  id BIGSERIAL PRIMARY KEY,
+  post_id BIGINT NOT NULL REFERENCES posts (id) ON DELETE CASCADE,
+  number INTEGER NOT NULL,
+  user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE RESTRICT,
+  title TEXT NOT NULL,
+  content TEXT NOT NULL,
+  excerpt TEXT,
+  image_url TEXT,
+  changed_fields JSONB NOT NULL DEFAULT '[]',
+  restored_from INTEGER,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_post_revisions_post_number ON post_revisions (post_id, number);
+
+INSERT INTO post_revisions (post_id, number, user_id, title, content, excerpt, image_url, changed_fields, created_at)
+SELECT id, 1, user_id, title, content, excerpt, image_url, '[]', updated_at
+FROM posts
+WHERE NOT EXISTS (SELECT 1 FROM post_revisions WHERE post_revisions.post_id = posts.id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS post_revisions;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS content_format VARCHAR(20) NOT NULL DEFAULT 'markdown';
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS content_html TEXT;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS toc JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS reading_time INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS excerpt_generated BOOLEAN NOT NULL DEFAULT false;
+
+-- Rendered content is filled in by the server on startup.
+CREATE INDEX IF NOT EXISTS idx_posts_content_html_pending ON posts (id) WHERE content_html IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_posts_content_html_pending;
+ALTER TABLE posts DROP COLUMN IF EXISTS excerpt_generated;
+ALTER TABLE posts DROP COLUMN IF EXISTS reading_time;
+ALTER TABLE posts DROP COLUMN IF EXISTS toc;
+ALTER TABLE posts DROP COLUMN IF EXISTS content_html;
+ALTER TABLE posts DROP COLUMN IF EXISTS content_format;
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS gallery_images (
+  id BIGSERIAL PRIMARY KEY,
+  post_id BIGINT REFERENCES posts (id) ON DELETE CASCADE,
+  trip_id BIGINT REFERENCES trips (id) ON DELETE CASCADE,
+  media_id BIGINT NOT NULL REFERENCES media (id) ON DELETE CASCADE,
+  position INTEGER NOT NULL,
+  caption TEXT,
+  alt_text TEXT,
+  taken_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  CONSTRAINT chk_gallery_images_owner CHECK ((post_id IS NULL) <> (trip_id IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_gallery_images_post_id ON gallery_images (post_id, position);
+CREATE INDEX IF NOT EXISTS idx_gallery_images_trip_id ON gallery_images (trip_id, position);
+CREATE INDEX IF NOT EXISTS idx_gallery_images_media_id ON gallery_images (media_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS gallery_images;
+-- +goose StatementEnd
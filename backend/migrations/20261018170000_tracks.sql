@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS tracks (
+  id BIGSERIAL PRIMARY KEY,
+  post_id BIGINT REFERENCES posts (id) ON DELETE CASCADE,
+  trip_id BIGINT REFERENCES trips (id) ON DELETE CASCADE,
+  user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  name TEXT NOT NULL,
+  distance DOUBLE PRECISION NOT NULL,
+  elevation_gain DOUBLE PRECISION NOT NULL,
+  duration BIGINT NOT NULL,
+  started_at TIMESTAMPTZ,
+  ended_at TIMESTAMPTZ,
+  min_latitude DOUBLE PRECISION NOT NULL,
+  min_longitude DOUBLE PRECISION NOT NULL,
+  max_latitude DOUBLE PRECISION NOT NULL,
+  max_longitude DOUBLE PRECISION NOT NULL,
+  point_count INTEGER NOT NULL,
+  points JSONB NOT NULL DEFAULT '[]',
+  simplified_points JSONB NOT NULL DEFAULT '[]',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  CONSTRAINT chk_tracks_owner CHECK ((post_id IS NULL) <> (trip_id IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_tracks_post_id ON tracks (post_id);
+CREATE INDEX IF NOT EXISTS idx_tracks_trip_id ON tracks (trip_id);
+CREATE INDEX IF NOT EXISTS idx_tracks_user_id ON tracks (user_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS tracks;
+-- +goose StatementEnd
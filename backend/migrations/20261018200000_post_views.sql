@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS view_count BIGINT NOT NULL DEFAULT 0;
+
+-- Random per-day salts for hashing visitors. A salt is deleted once its day
+-- is over, after which the stored hashes can no longer be linked to anyone.
+CREATE TABLE IF NOT EXISTS view_salts (
+  day DATE PRIMARY KEY,
+  salt BYTEA NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS post_view_visitors (
+  post_id BIGINT NOT NULL REFERENCES posts (id) ON DELETE CASCADE,
+  day DATE NOT NULL,
+  visitor TEXT NOT NULL,
+  PRIMARY KEY (post_id, day, visitor)
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_view_visitors_day ON post_view_visitors (day);
+
+CREATE TABLE IF NOT EXISTS post_view_daily (
+  post_id BIGINT NOT NULL REFERENCES posts (id) ON DELETE CASCADE,
+  day DATE NOT NULL,
+  views BIGINT NOT NULL DEFAULT 0,
+  PRIMARY KEY (post_id, day)
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_view_daily_day ON post_view_daily (day);
+CREATE INDEX IF NOT EXISTS idx_post_reactions_created_at ON post_reactions (created_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_post_reactions_created_at;
+DROP TABLE IF EXISTS post_view_daily;
+DROP TABLE IF EXISTS post_view_visitors;
+DROP TABLE IF EXISTS view_salts;
+ALTER TABLE posts DROP COLUMN IF EXISTS view_count;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Cursor pagination seeks on (created_at, id).
+CREATE INDEX IF NOT EXISTS idx_posts_created_at_id ON posts (created_at, id);
+CREATE INDEX IF NOT EXISTS idx_comments_post_id_created_at_id ON comments (post_id, created_at, id) WHERE parent_id IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_comments_post_id_created_at_id;
+DROP INDEX IF EXISTS idx_posts_created_at_id;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Preview links carry the version they were issued for; bumping it revokes
+-- every outstanding link of the post.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS preview_version INTEGER NOT NULL DEFAULT 0;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE posts DROP COLUMN IF EXISTS preview_version;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS post_authors (
+  post_id BIGINT NOT NULL REFERENCES posts (id) ON DELETE CASCADE,
+  user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  role VARCHAR(20) NOT NULL,
+  invited_by BIGINT REFERENCES users (id) ON DELETE SET NULL,
+  accepted_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (post_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_authors_user_id ON post_authors (user_id, accepted_at);
+
+-- Every existing post is owned by its creator.
+INSERT INTO post_authors (post_id, user_id, role, accepted_at, created_at)
+SELECT id, user_id, 'owner', created_at, created_at FROM posts
+ON CONFLICT DO NOTHING;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS post_authors;
+-- +goose StatementEnd
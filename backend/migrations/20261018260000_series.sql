@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS series (
+  id BIGSERIAL PRIMARY KEY,
+  title TEXT NOT NULL,
+  description TEXT,
+  user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_series_user_id ON series (user_id);
+CREATE INDEX IF NOT EXISTS idx_series_created_at ON series (created_at);
+
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS series_id BIGINT REFERENCES series (id) ON DELETE SET NULL;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS series_position INTEGER;
+CREATE INDEX IF NOT EXISTS idx_posts_series_id ON posts (series_id, series_position);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_posts_series_id;
+ALTER TABLE posts DROP COLUMN IF EXISTS series_position;
+ALTER TABLE posts DROP COLUMN IF EXISTS series_id;
+DROP TABLE IF EXISTS series;
+-- +goose StatementEnd
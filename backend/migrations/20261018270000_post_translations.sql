@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS language VARCHAR(20) NOT NULL DEFAULT 'en';
+
+CREATE TABLE IF NOT EXISTS post_translations (
+  id BIGSERIAL PRIMARY KEY,
+  post_id BIGINT NOT NULL REFERENCES posts (id) ON DELETE CASCADE,
+  language VARCHAR(20) NOT NULL,
+  title TEXT NOT NULL,
+  content TEXT NOT NULL,
+  content_format VARCHAR(20) NOT NULL DEFAULT 'markdown',
+  content_html TEXT,
+  toc JSONB NOT NULL DEFAULT '[]',
+  reading_time INTEGER NOT NULL DEFAULT 0,
+  excerpt TEXT,
+  excerpt_generated BOOLEAN NOT NULL DEFAULT false,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_post_translations_post_language ON post_translations (post_id, language);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS post_translations;
+ALTER TABLE posts DROP COLUMN IF EXISTS language;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS edited_at TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS comment_revisions (
+  id BIGSERIAL PRIMARY KEY,
+  comment_id BIGINT NOT NULL REFERENCES comments (id) ON DELETE CASCADE,
+  user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  content TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_comment_revisions_comment_id ON comment_revisions (comment_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS comment_revisions;
+ALTER TABLE comments DROP COLUMN IF EXISTS edited_at;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS path TEXT NOT NULL DEFAULT '';
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS depth INTEGER NOT NULL DEFAULT 0;
+
+WITH RECURSIVE tree AS (
+  SELECT id, 0 AS depth, '/' || id || '/' AS path
+  FROM comments
+  WHERE parent_id IS NULL
+  UNION ALL
+  SELECT comments.id, tree.depth + 1, tree.path || comments.id || '/'
+  FROM comments
+  JOIN tree ON comments.parent_id = tree.id
+)
+UPDATE comments
+SET path = tree.path, depth = tree.depth
+FROM tree
+WHERE comments.id = tree.id;
+
+CREATE INDEX IF NOT EXISTS idx_comments_path ON comments (path text_pattern_ops);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_comments_path;
+ALTER TABLE comments DROP COLUMN IF EXISTS depth;
+ALTER TABLE comments DROP COLUMN IF EXISTS path;
+-- +goose StatementEnd
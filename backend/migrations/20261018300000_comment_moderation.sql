@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'approved';
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS moderated_by BIGINT REFERENCES users (id) ON DELETE SET NULL;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS moderated_at TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS idx_comments_status_created_at ON comments (status, created_at) WHERE deleted_at IS NULL;
+
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS comment_moderation VARCHAR(20);
+
+CREATE TABLE IF NOT EXISTS notifications (
+  id BIGSERIAL PRIMARY KEY,
+  user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  type VARCHAR(30) NOT NULL,
+  actor_id BIGINT REFERENCES users (id) ON DELETE SET NULL,
+  post_id BIGINT REFERENCES posts (id) ON DELETE CASCADE,
+  comment_id BIGINT REFERENCES comments (id) ON DELETE CASCADE,
+  read_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user_id_created_at ON notifications (user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_notifications_unread ON notifications (user_id) WHERE read_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS notifications;
+ALTER TABLE posts DROP COLUMN IF EXISTS comment_moderation;
+DROP INDEX IF EXISTS idx_comments_status_created_at;
+ALTER TABLE comments DROP COLUMN IF EXISTS moderated_at;
+ALTER TABLE comments DROP COLUMN IF EXISTS moderated_by;
+ALTER TABLE comments DROP COLUMN IF EXISTS status;
+-- +goose StatementEnd
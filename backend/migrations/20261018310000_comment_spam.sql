@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS spam_score DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS spam_reasons JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS spam_trained VARCHAR(10);
+
+CREATE TABLE IF NOT EXISTS spam_tokens (
+  token VARCHAR(100) PRIMARY KEY,
+  spam BIGINT NOT NULL DEFAULT 0,
+  ham BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS spam_corpora (
+  class VARCHAR(10) PRIMARY KEY,
+  documents BIGINT NOT NULL DEFAULT 0
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS spam_corpora;
+DROP TABLE IF EXISTS spam_tokens;
+ALTER TABLE comments DROP COLUMN IF EXISTS spam_trained;
+ALTER TABLE comments DROP COLUMN IF EXISTS spam_reasons;
+ALTER TABLE comments DROP COLUMN IF EXISTS spam_score;
+-- +goose StatementEnd